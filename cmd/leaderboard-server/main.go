@@ -0,0 +1,187 @@
+// leaderboard-server 以 HTTP/JSON 接口提供排行榜服务
+//
+// 用法：
+//
+//	leaderboard-server -addr :8080 -impl skiplist -order desc -ranking ordinal -wal data/board.wal -resp :6379 -grpc :9090
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"game/grpcapi"
+	"game/grpcapi/leaderboardpb"
+	"game/httpapi"
+	"game/leaderboard"
+	"game/livefeed"
+	"game/persist"
+	"game/resp"
+)
+
+var rankingModes = map[string]leaderboard.RankingMode{
+	"ordinal":  leaderboard.OrdinalRanking,
+	"standard": leaderboard.StandardCompetitionRanking,
+	"modified": leaderboard.ModifiedCompetitionRanking,
+	"dense":    leaderboard.DenseRanking,
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run 启动服务直到收到退出信号，出错时返回，确保已打开的预写日志在退出前关闭
+func run() error {
+	addr := flag.String("addr", ":8080", "HTTP 监听地址")
+	impl := flag.String("impl", "skiplist", "排行榜实现：skiplist 或 linkedlist")
+	order := flag.String("order", "desc", "排序方向：desc（分数从高到低）或 asc（分数从低到高）")
+	ranking := flag.String("ranking", "ordinal", "排名策略：ordinal、standard、modified 或 dense")
+	walPath := flag.String("wal", "", "预写日志路径，为空时不持久化")
+	respAddr := flag.String("resp", "", "Redis 协议监听地址，为空时不启用")
+	grpcAddr := flag.String("grpc", "", "gRPC 监听地址，为空时不启用")
+	flag.Parse()
+
+	// 排名变化时立即唤醒实时推送和 gRPC 订阅，不必等待轮询
+	bus := leaderboard.NewEventBus(leaderboard.EventBusConfig{})
+	board, err := newBoard(*impl, *order, *ranking, bus)
+	if err != nil {
+		return err
+	}
+	if *walPath != "" {
+		durable, err := persist.OpenDurable(*walPath, board, persist.LogOptions{})
+		if err != nil {
+			return fmt.Errorf("open wal: %w", err)
+		}
+		defer durable.Close()
+		// 读操作直接访问内存排行榜，密集排名查询仍由内存排行榜提供
+		if dense, ok := board.(leaderboard.DenseRanker); ok {
+			board = durableDenseBoard{durable, dense}
+		} else {
+			board = durable
+		}
+	}
+
+	// 先监听所有地址，任意一个失败时直接返回，此时还没有开始处理请求
+	httpLn, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	defer httpLn.Close()
+	var respLn, grpcLn net.Listener
+	if *respAddr != "" {
+		if respLn, err = net.Listen("tcp", *respAddr); err != nil {
+			return err
+		}
+		defer respLn.Close()
+	}
+	if *grpcAddr != "" {
+		if grpcLn, err = net.Listen("tcp", *grpcAddr); err != nil {
+			return err
+		}
+		defer grpcLn.Close()
+	}
+
+	// /ws 为观战客户端的实时推送，其余路径为 HTTP/JSON 接口
+	feed := livefeed.NewFeed(board)
+	mux := http.NewServeMux()
+	mux.Handle("/ws", feed)
+	mux.Handle("/", httpapi.NewHandler(board))
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	var respSrv *resp.Server
+	if respLn != nil {
+		respSrv = resp.NewServer(resp.SingleBoard(board))
+	}
+	var grpcSrv *grpc.Server
+	var grpcService *grpcapi.Server
+	if grpcLn != nil {
+		grpcSrv = grpc.NewServer()
+		grpcService = grpcapi.NewServer(board)
+		leaderboardpb.RegisterLeaderboardServer(grpcSrv, grpcService)
+	}
+
+	// 开始处理请求前订阅，第一个写请求产生的事件也能唤醒订阅者
+	cancel := bus.Subscribe(func(leaderboard.Event) {
+		feed.Notify()
+		if grpcService != nil {
+			grpcService.Notify()
+		}
+	})
+	defer cancel()
+
+	if respSrv != nil {
+		go respSrv.Serve(respLn)
+		log.Printf("leaderboard RESP server listening on %s", *respAddr)
+	}
+	if grpcSrv != nil {
+		go grpcSrv.Serve(grpcLn)
+		log.Printf("leaderboard gRPC server listening on %s", *grpcAddr)
+	}
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if respSrv != nil {
+			respSrv.Close()
+		}
+		if grpcSrv != nil {
+			grpcSrv.Stop() // 订阅流不会自行结束，不能等待其完成
+		}
+		srv.Shutdown(ctx)
+	}()
+
+	log.Printf("leaderboard server listening on %s", *addr)
+	if err := srv.Serve(httpLn); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// durableDenseBoard 持久化排行榜，同时支持密集排名查询
+type durableDenseBoard struct {
+	*persist.DurableBoard
+	leaderboard.DenseRanker
+}
+
+// newBoard 根据命令行参数创建排行榜
+func newBoard(impl, order, ranking string, bus *leaderboard.EventBus) (leaderboard.LeaderboardService, error) {
+	opts := []leaderboard.Option{leaderboard.WithEventBus(bus)}
+	switch order {
+	case "desc":
+		opts = append(opts, leaderboard.WithOrder(leaderboard.Descending))
+	case "asc":
+		opts = append(opts, leaderboard.WithOrder(leaderboard.Ascending))
+	default:
+		return nil, fmt.Errorf("unknown order %q", order)
+	}
+	mode, ok := rankingModes[ranking]
+	if !ok {
+		return nil, fmt.Errorf("unknown ranking mode %q", ranking)
+	}
+	opts = append(opts, leaderboard.WithRankingMode(mode))
+
+	switch impl {
+	case "skiplist":
+		return leaderboard.NewLeaderboardSkipList(opts...), nil
+	case "linkedlist":
+		return leaderboard.NewLeaderboardLinkedList(opts...), nil
+	}
+	return nil, fmt.Errorf("unknown implementation %q", impl)
+}
@@ -0,0 +1,225 @@
+package crdt
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"game/leaderboard"
+)
+
+// Config 副本配置，除 Replica 外同一排行榜的所有副本必须一致
+type Config struct {
+	Replica     string                  // 本副本的ID，各副本必须不同
+	Mode        Mode                    // 寄存器的合并方式
+	Order       leaderboard.SortOrder   // 排序方向，Max 模式按它判断成绩好坏
+	RankingMode leaderboard.RankingMode // 排名策略，为 nil 时使用序数排名
+}
+
+// PlayerState 一个玩家的寄存器
+type PlayerState struct {
+	PlayerID string
+	Register
+}
+
+// Delta 副本之间传输的状态，只包含自某个版本以来发生变化的玩家，按玩家ID排序
+type Delta struct {
+	Replica string        // 生成增量的副本
+	Version uint64        // 生成增量时副本的版本，下次从该版本开始导出
+	Players []PlayerState // 变化的玩家，包括墓碑
+}
+
+// Board 可合并的排行榜副本，实现 LeaderboardService，可以并发使用
+//
+// 写操作更新本地寄存器，合并其他副本的增量后，各副本的查询结果一致。
+// 更新策略由 Mode 决定，UpdateScore 在 LWW 模式下覆盖旧成绩，在 Max 模式下只保留更好的成绩；
+// IncrementScore 在本副本当前成绩的基础上计算新成绩后写入，不同副本并发的增量不会累加，需要计数语义时应在单个区域写入
+type Board struct {
+	leaderboard.LeaderboardReader
+
+	cfg  Config
+	view *leaderboard.LeaderboardSkipList // 由寄存器中未删除的玩家构成的排行榜，用于查询
+	now  func() time.Time
+
+	mu      sync.Mutex
+	players map[string]*playerState
+	clock   uint64 // 已见过的最大写入时钟
+	version uint64 // 本地版本，寄存器每次变化加一
+}
+
+type playerState struct {
+	reg     Register
+	version uint64 // 寄存器最后一次变化时的本地版本
+}
+
+// NewBoard 创建空的副本
+func NewBoard(cfg Config) *Board {
+	if cfg.RankingMode == nil {
+		cfg.RankingMode = leaderboard.OrdinalRanking
+	}
+	view := leaderboard.NewLeaderboardSkipList(
+		leaderboard.WithOrder(cfg.Order),
+		leaderboard.WithRankingMode(cfg.RankingMode),
+		leaderboard.WithUpdatePolicy(leaderboard.PolicyReplace),
+		leaderboard.WithTimestampMode(leaderboard.TimestampAlways),
+	)
+	return &Board{
+		LeaderboardReader: view,
+		cfg:               cfg,
+		view:              view,
+		now:               time.Now,
+		players:           make(map[string]*playerState),
+	}
+}
+
+// Version 返回副本当前的版本
+func (b *Board) Version() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.version
+}
+
+// DeltaSince 导出本地版本 version 之后发生变化的玩家，包括合并自其他副本的变化
+// 调用方为每个对端记录上次发送的 Delta.Version，下次从该版本导出；version 为 0 时导出全部状态
+func (b *Board) DeltaSince(version uint64) Delta {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	d := Delta{Replica: b.cfg.Replica, Version: b.version}
+	for id, ps := range b.players {
+		if ps.version > version {
+			d.Players = append(d.Players, PlayerState{PlayerID: id, Register: ps.reg})
+		}
+	}
+	sort.Slice(d.Players, func(i, j int) bool { return d.Players[i].PlayerID < d.Players[j].PlayerID })
+	return d
+}
+
+// State 导出全部状态，等同于 DeltaSince(0)
+func (b *Board) State() Delta {
+	return b.DeltaSince(0)
+}
+
+// Merge 合并其他副本的增量，返回寄存器发生变化的玩家数
+// 合并满足交换律、结合律和幂等律，增量可以乱序、重复到达
+func (b *Board) Merge(d Delta) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	changed := 0
+	for _, p := range d.Players {
+		if p.Clock > b.clock {
+			b.clock = p.Clock
+		}
+		if _, ok := b.set(p.PlayerID, p.Register); ok {
+			changed++
+		}
+	}
+	return changed
+}
+
+// tick 返回本地写入使用的时钟，调用方需持有 b.mu
+func (b *Board) tick() uint64 {
+	b.clock++
+	if wall := uint64(b.now().UnixNano()); wall > b.clock {
+		b.clock = wall
+	}
+	return b.clock
+}
+
+// set 寄存器 reg 胜过当前寄存器时写入并更新排行榜，返回排行榜的更新结果和是否写入，调用方需持有 b.mu
+func (b *Board) set(playerID string, reg Register) (leaderboard.UpdateResult, bool) {
+	ps, ok := b.players[playerID]
+	if ok && !reg.wins(ps.reg, b.cfg.Mode, b.cfg.Order) {
+		return leaderboard.UpdateResult{}, false
+	}
+	if !ok {
+		ps = &playerState{}
+		b.players[playerID] = ps
+	}
+	b.version++
+	ps.reg, ps.version = reg, b.version
+
+	if reg.Deleted {
+		b.view.RemovePlayer(playerID)
+		return leaderboard.UpdateResult{}, true
+	}
+	return b.view.UpdateScore(playerID, reg.Score, reg.Timestamp), true
+}
+
+// write 以本地时钟写入成绩，调用方需持有 b.mu
+func (b *Board) write(playerID string, score int, timestamp time.Time) leaderboard.UpdateResult {
+	reg := Register{Score: score, Timestamp: timestamp, Clock: b.tick(), Replica: b.cfg.Replica}
+	if ps, ok := b.players[playerID]; ok {
+		reg.Epoch = ps.reg.Epoch
+	}
+	if res, ok := b.set(playerID, reg); ok {
+		return res
+	}
+	info, existed := b.view.GetPlayerRank(playerID)
+	return leaderboard.UpdateResult{Old: info, New: info, Existed: existed}
+}
+
+// remove 为未删除的玩家写入墓碑，调用方需持有 b.mu
+func (b *Board) remove(playerID string) bool {
+	ps, ok := b.players[playerID]
+	if !ok || ps.reg.Deleted {
+		return false
+	}
+	reg := Register{Clock: b.tick(), Replica: b.cfg.Replica, Deleted: true}
+	if b.cfg.Mode == Max {
+		reg.Epoch = ps.reg.Epoch + 1
+	}
+	b.set(playerID, reg)
+	return true
+}
+
+// UpdateScore 写入成绩，Max 模式下成绩不比当前更好时不生效
+func (b *Board) UpdateScore(playerID string, score int, timestamp time.Time) leaderboard.UpdateResult {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.write(playerID, score, timestamp)
+}
+
+// IncrementScore 在本副本当前成绩的基础上增加分数后写入
+func (b *Board) IncrementScore(playerID string, delta int, timestamp time.Time) leaderboard.RankInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	score := delta
+	if info, ok := b.view.GetPlayerRank(playerID); ok {
+		score += info.Score
+	}
+	return b.write(playerID, score, timestamp).New
+}
+
+// RemovePlayer 删除玩家，写入墓碑
+func (b *Board) RemovePlayer(playerID string) bool {
+	return b.RemovePlayers(playerID) == 1
+}
+
+// RemovePlayers 批量删除玩家，写入墓碑
+func (b *Board) RemovePlayers(playerIDs ...string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	removed := 0
+	for _, id := range playerIDs {
+		if b.remove(id) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Reset 删除本副本当前的全部玩家，其他副本与之并发的写入按合并规则保留
+func (b *Board) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id := range b.players {
+		b.remove(id)
+	}
+}
@@ -0,0 +1,166 @@
+package crdt
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"game/leaderboard"
+)
+
+// randomOps 在副本上执行随机写操作，偶尔与 peer 交换增量
+func randomOps(rng *rand.Rand, b, peer *Board, n int, base time.Time) {
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("p%d", rng.Intn(20))
+		ts := base.Add(time.Duration(rng.Intn(50)) * time.Second)
+		switch op := rng.Intn(10); {
+		case op < 5:
+			b.UpdateScore(id, rng.Intn(100), ts)
+		case op < 7:
+			b.IncrementScore(id, rng.Intn(10)-3, ts)
+		case op < 9:
+			b.RemovePlayers(id)
+		default:
+			b.Merge(peer.DeltaSince(uint64(rng.Intn(int(peer.Version() + 1)))))
+		}
+	}
+}
+
+func TestBoard_MergeConverges(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, mode := range []Mode{LWW, Max} {
+		for _, order := range []leaderboard.SortOrder{leaderboard.Descending, leaderboard.Ascending} {
+			rng := rand.New(rand.NewSource(int64(mode)*10 + int64(order)))
+			regions := make([]*Board, 3)
+			for i := range regions {
+				regions[i] = NewBoard(Config{Replica: fmt.Sprintf("r%d", i), Mode: mode, Order: order})
+			}
+			for round := 0; round < 5; round++ {
+				for i, b := range regions {
+					randomOps(rng, b, regions[(i+1)%3], 40, base)
+				}
+			}
+
+			// 以不同顺序合并全部副本的状态，重复合并不影响结果
+			var want string
+			for _, perm := range [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0, 1}} {
+				merged := NewBoard(Config{Replica: "merged", Mode: mode, Order: order})
+				for _, i := range perm {
+					merged.Merge(regions[i].State())
+				}
+				got := fmt.Sprint(merged.GetTopN(100), merged.State().Players)
+				if want == "" {
+					want = got
+				} else if got != want {
+					t.Errorf("mode %v order %v: merge order %v diverged:\n got %s\nwant %s", mode, order, perm, got, want)
+				}
+			}
+
+			// 副本之间两两交换增量后与合并结果一致
+			for _, b := range regions {
+				for _, peer := range regions {
+					b.Merge(peer.State())
+				}
+			}
+			for _, b := range regions {
+				if got := fmt.Sprint(b.GetTopN(100), b.State().Players); got != want {
+					t.Errorf("mode %v order %v: region %s = %s; want %s", mode, order, b.cfg.Replica, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestBoard_RegisterSemantics(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// LWW：后写入的成绩即使更差也会覆盖先写入的成绩
+	east := NewBoard(Config{Replica: "east", Mode: LWW})
+	west := NewBoard(Config{Replica: "west", Mode: LWW})
+	east.UpdateScore("a", 100, base)
+	west.Merge(east.State())
+	west.UpdateScore("a", 50, base)
+	east.Merge(west.State())
+	if info, _ := east.GetPlayerRank("a"); info.Score != 50 {
+		t.Errorf("LWW merged score = %d; want 50", info.Score)
+	}
+
+	// 墓碑阻止已删除的玩家被旧增量复活
+	stale := east.State()
+	east.RemovePlayer("a")
+	east.Merge(stale)
+	if _, ok := east.GetPlayerRank("a"); ok {
+		t.Error("LWW player resurrected by stale delta")
+	}
+
+	// Max：保留最好成绩，与写入顺序无关；升序排行榜中分数低者更好
+	for _, order := range []leaderboard.SortOrder{leaderboard.Descending, leaderboard.Ascending} {
+		x := NewBoard(Config{Replica: "x", Mode: Max, Order: order})
+		y := NewBoard(Config{Replica: "y", Mode: Max, Order: order})
+		x.UpdateScore("a", 30, base)
+		y.UpdateScore("a", 10, base)
+		x.UpdateScore("a", 20, base)
+		x.Merge(y.State())
+		want := 30
+		if order == leaderboard.Ascending {
+			want = 10
+		}
+		if info, _ := x.GetPlayerRank("a"); info.Score != want {
+			t.Errorf("Max order %v merged score = %d; want %d", order, info.Score, want)
+		}
+	}
+
+	// Max：删除开启新的轮次，与删除并发的旧轮次成绩被丢弃，之后的写入重新计算最好成绩
+	x := NewBoard(Config{Replica: "x", Mode: Max})
+	y := NewBoard(Config{Replica: "y", Mode: Max})
+	x.UpdateScore("a", 10, base)
+	y.Merge(x.State())
+	x.RemovePlayer("a")
+	y.UpdateScore("a", 90, base)
+	x.Merge(y.State())
+	if _, ok := x.GetPlayerRank("a"); ok {
+		t.Error("Max write concurrent with removal survived")
+	}
+	x.UpdateScore("a", 5, base)
+	y.Merge(x.State())
+	if info, _ := y.GetPlayerRank("a"); info.Score != 5 {
+		t.Errorf("Max score after removal = %d; want 5", info.Score)
+	}
+}
+
+func TestBoard_DeltaSince(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := NewBoard(Config{Replica: "src"})
+	dst := NewBoard(Config{Replica: "dst"})
+	for i := 0; i < 100; i++ {
+		src.UpdateScore(fmt.Sprintf("p%d", i), i, base)
+	}
+	d := src.State()
+	if n := dst.Merge(d); n != 100 {
+		t.Fatalf("Merge(full state) = %d; want 100", n)
+	}
+
+	// 只导出上次同步之后变化的玩家，包括墓碑
+	src.UpdateScore("p1", 1000, base)
+	src.RemovePlayer("p2")
+	src.UpdateScore("p3", 3, base) // 覆盖为相同成绩也是一次写入
+	delta := src.DeltaSince(d.Version)
+	var ids []string
+	for _, p := range delta.Players {
+		ids = append(ids, p.PlayerID)
+	}
+	if fmt.Sprint(ids) != "[p1 p2 p3]" {
+		t.Errorf("DeltaSince() players = %v; want [p1 p2 p3]", ids)
+	}
+	dst.Merge(delta)
+	if n := dst.Merge(delta); n != 0 {
+		t.Errorf("Merge(same delta) again = %d; want 0", n)
+	}
+	if got, want := fmt.Sprint(dst.GetTopN(100)), fmt.Sprint(src.GetTopN(100)); got != want {
+		t.Errorf("dst GetTopN() = %s; want %s", got, want)
+	}
+	if d := src.DeltaSince(delta.Version); len(d.Players) != 0 {
+		t.Errorf("DeltaSince(latest) = %+v; want empty", d.Players)
+	}
+}
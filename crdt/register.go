@@ -0,0 +1,84 @@
+// Package crdt 实现可合并的排行榜，用于多个区域同时写入的多活部署
+//
+// 每个区域持有一个副本（Board），各自独立接受写入，互相发送增量后合并。副本以玩家ID为键保存寄存器，
+// 寄存器的合并满足交换律、结合律和幂等律：无论以什么顺序、合并多少次，收到相同写入的副本最终状态相同，
+// GetTopN 等查询结果也相同。寄存器有两种合并方式：
+//
+//   - LWW（最后写入者胜）：保留写入时钟最大的写入，适用于以最新成绩为准的排行榜
+//   - Max（最好成绩）：保留按排序方向最好的成绩，适用于只记录历史最好成绩的排行榜
+//
+// 删除以墓碑表示，墓碑同样参与合并并一直保留，避免已删除的玩家被旧的写入复活
+package crdt
+
+import (
+	"time"
+
+	"game/leaderboard"
+)
+
+// Mode 寄存器的合并方式，同一排行榜的所有副本必须一致
+type Mode int
+
+const (
+	LWW Mode = iota // 最后写入者胜，删除同样按写入时钟参与比较
+	Max             // 保留最好成绩；删除开启新的轮次，丢弃之前轮次的全部成绩，包括与删除并发的写入
+)
+
+func (m Mode) String() string {
+	switch m {
+	case LWW:
+		return "lww"
+	case Max:
+		return "max"
+	}
+	return "unknown"
+}
+
+// Register 一个玩家的寄存器
+type Register struct {
+	Score     int
+	Timestamp time.Time // 得分时间，用于同分排序
+	Clock     uint64    // 写入时副本的时钟，取墙上时间与已见过的最大时钟加一中的较大者
+	Replica   string    // 写入的副本，时钟相同时按副本ID比较，保证合并结果确定
+	Epoch     uint64    // Max 模式下的轮次，每次删除加一；LWW 模式下恒为 0
+	Deleted   bool      // 墓碑，玩家已被删除
+}
+
+// newer 按写入时钟和副本ID比较两个寄存器
+func (r Register) newer(o Register) bool {
+	if r.Clock != o.Clock {
+		return r.Clock > o.Clock
+	}
+	return r.Replica > o.Replica
+}
+
+// better 判断 r 的成绩是否比 o 更好：分数按排序方向更好，同分时更早达到，成绩相同时按写入时钟和副本ID确定
+func (r Register) better(o Register, order leaderboard.SortOrder) bool {
+	if r.Score != o.Score {
+		if order == leaderboard.Ascending {
+			return r.Score < o.Score
+		}
+		return r.Score > o.Score
+	}
+	if !r.Timestamp.Equal(o.Timestamp) {
+		return r.Timestamp.Before(o.Timestamp)
+	}
+	return r.newer(o)
+}
+
+// wins 判断合并时 r 是否胜过 o，两者相同时返回 false
+func (r Register) wins(o Register, mode Mode, order leaderboard.SortOrder) bool {
+	if mode == LWW {
+		return r.newer(o)
+	}
+	if r.Epoch != o.Epoch {
+		return r.Epoch > o.Epoch
+	}
+	if r.Deleted != o.Deleted {
+		return !r.Deleted // 同一轮次中有成绩即胜过墓碑
+	}
+	if r.Deleted {
+		return r.newer(o)
+	}
+	return r.better(o, order)
+}
@@ -0,0 +1,4 @@
+// Package leaderboardpb 排行榜 gRPC 接口的协议定义及生成代码
+package leaderboardpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative leaderboard.proto
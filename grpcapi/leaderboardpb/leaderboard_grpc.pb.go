@@ -0,0 +1,336 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: leaderboard.proto
+
+// 排行榜 gRPC 接口，与 Go 包中的 LeaderboardService 一一对应
+
+package leaderboardpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Leaderboard_UpdateScore_FullMethodName        = "/leaderboard.v1.Leaderboard/UpdateScore"
+	Leaderboard_BatchUpdateScores_FullMethodName  = "/leaderboard.v1.Leaderboard/BatchUpdateScores"
+	Leaderboard_GetPlayerRank_FullMethodName      = "/leaderboard.v1.Leaderboard/GetPlayerRank"
+	Leaderboard_GetTopN_FullMethodName            = "/leaderboard.v1.Leaderboard/GetTopN"
+	Leaderboard_GetPlayerRankRange_FullMethodName = "/leaderboard.v1.Leaderboard/GetPlayerRankRange"
+	Leaderboard_WatchTopN_FullMethodName          = "/leaderboard.v1.Leaderboard/WatchTopN"
+)
+
+// LeaderboardClient is the client API for Leaderboard service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LeaderboardClient interface {
+	// 更新玩家分数
+	UpdateScore(ctx context.Context, in *UpdateScoreRequest, opts ...grpc.CallOption) (*UpdateScoreResponse, error)
+	// 批量更新玩家分数，按请求顺序依次执行，返回每条更新的结果
+	BatchUpdateScores(ctx context.Context, in *BatchUpdateScoresRequest, opts ...grpc.CallOption) (*BatchUpdateScoresResponse, error)
+	// 获取玩家当前排名，玩家不存在时返回 NOT_FOUND
+	GetPlayerRank(ctx context.Context, in *GetPlayerRankRequest, opts ...grpc.CallOption) (*GetPlayerRankResponse, error)
+	// 获取排行榜前 N 名
+	GetTopN(ctx context.Context, in *GetTopNRequest, opts ...grpc.CallOption) (*GetTopNResponse, error)
+	// 获取玩家周边排名，玩家不存在时返回 NOT_FOUND
+	GetPlayerRankRange(ctx context.Context, in *GetPlayerRankRangeRequest, opts ...grpc.CallOption) (*GetPlayerRankRangeResponse, error)
+	// 订阅前 N 名的变化：订阅后立即推送一次当前前 N 名，之后每当前 N 名发生变化时推送变化后的完整列表
+	WatchTopN(ctx context.Context, in *WatchTopNRequest, opts ...grpc.CallOption) (Leaderboard_WatchTopNClient, error)
+}
+
+type leaderboardClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLeaderboardClient(cc grpc.ClientConnInterface) LeaderboardClient {
+	return &leaderboardClient{cc}
+}
+
+func (c *leaderboardClient) UpdateScore(ctx context.Context, in *UpdateScoreRequest, opts ...grpc.CallOption) (*UpdateScoreResponse, error) {
+	out := new(UpdateScoreResponse)
+	err := c.cc.Invoke(ctx, Leaderboard_UpdateScore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardClient) BatchUpdateScores(ctx context.Context, in *BatchUpdateScoresRequest, opts ...grpc.CallOption) (*BatchUpdateScoresResponse, error) {
+	out := new(BatchUpdateScoresResponse)
+	err := c.cc.Invoke(ctx, Leaderboard_BatchUpdateScores_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardClient) GetPlayerRank(ctx context.Context, in *GetPlayerRankRequest, opts ...grpc.CallOption) (*GetPlayerRankResponse, error) {
+	out := new(GetPlayerRankResponse)
+	err := c.cc.Invoke(ctx, Leaderboard_GetPlayerRank_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardClient) GetTopN(ctx context.Context, in *GetTopNRequest, opts ...grpc.CallOption) (*GetTopNResponse, error) {
+	out := new(GetTopNResponse)
+	err := c.cc.Invoke(ctx, Leaderboard_GetTopN_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardClient) GetPlayerRankRange(ctx context.Context, in *GetPlayerRankRangeRequest, opts ...grpc.CallOption) (*GetPlayerRankRangeResponse, error) {
+	out := new(GetPlayerRankRangeResponse)
+	err := c.cc.Invoke(ctx, Leaderboard_GetPlayerRankRange_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardClient) WatchTopN(ctx context.Context, in *WatchTopNRequest, opts ...grpc.CallOption) (Leaderboard_WatchTopNClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Leaderboard_ServiceDesc.Streams[0], Leaderboard_WatchTopN_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &leaderboardWatchTopNClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Leaderboard_WatchTopNClient interface {
+	Recv() (*WatchTopNResponse, error)
+	grpc.ClientStream
+}
+
+type leaderboardWatchTopNClient struct {
+	grpc.ClientStream
+}
+
+func (x *leaderboardWatchTopNClient) Recv() (*WatchTopNResponse, error) {
+	m := new(WatchTopNResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LeaderboardServer is the server API for Leaderboard service.
+// All implementations must embed UnimplementedLeaderboardServer
+// for forward compatibility
+type LeaderboardServer interface {
+	// 更新玩家分数
+	UpdateScore(context.Context, *UpdateScoreRequest) (*UpdateScoreResponse, error)
+	// 批量更新玩家分数，按请求顺序依次执行，返回每条更新的结果
+	BatchUpdateScores(context.Context, *BatchUpdateScoresRequest) (*BatchUpdateScoresResponse, error)
+	// 获取玩家当前排名，玩家不存在时返回 NOT_FOUND
+	GetPlayerRank(context.Context, *GetPlayerRankRequest) (*GetPlayerRankResponse, error)
+	// 获取排行榜前 N 名
+	GetTopN(context.Context, *GetTopNRequest) (*GetTopNResponse, error)
+	// 获取玩家周边排名，玩家不存在时返回 NOT_FOUND
+	GetPlayerRankRange(context.Context, *GetPlayerRankRangeRequest) (*GetPlayerRankRangeResponse, error)
+	// 订阅前 N 名的变化：订阅后立即推送一次当前前 N 名，之后每当前 N 名发生变化时推送变化后的完整列表
+	WatchTopN(*WatchTopNRequest, Leaderboard_WatchTopNServer) error
+	mustEmbedUnimplementedLeaderboardServer()
+}
+
+// UnimplementedLeaderboardServer must be embedded to have forward compatible implementations.
+type UnimplementedLeaderboardServer struct {
+}
+
+func (UnimplementedLeaderboardServer) UpdateScore(context.Context, *UpdateScoreRequest) (*UpdateScoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateScore not implemented")
+}
+func (UnimplementedLeaderboardServer) BatchUpdateScores(context.Context, *BatchUpdateScoresRequest) (*BatchUpdateScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdateScores not implemented")
+}
+func (UnimplementedLeaderboardServer) GetPlayerRank(context.Context, *GetPlayerRankRequest) (*GetPlayerRankResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlayerRank not implemented")
+}
+func (UnimplementedLeaderboardServer) GetTopN(context.Context, *GetTopNRequest) (*GetTopNResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopN not implemented")
+}
+func (UnimplementedLeaderboardServer) GetPlayerRankRange(context.Context, *GetPlayerRankRangeRequest) (*GetPlayerRankRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlayerRankRange not implemented")
+}
+func (UnimplementedLeaderboardServer) WatchTopN(*WatchTopNRequest, Leaderboard_WatchTopNServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTopN not implemented")
+}
+func (UnimplementedLeaderboardServer) mustEmbedUnimplementedLeaderboardServer() {}
+
+// UnsafeLeaderboardServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LeaderboardServer will
+// result in compilation errors.
+type UnsafeLeaderboardServer interface {
+	mustEmbedUnimplementedLeaderboardServer()
+}
+
+func RegisterLeaderboardServer(s grpc.ServiceRegistrar, srv LeaderboardServer) {
+	s.RegisterService(&Leaderboard_ServiceDesc, srv)
+}
+
+func _Leaderboard_UpdateScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServer).UpdateScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Leaderboard_UpdateScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServer).UpdateScore(ctx, req.(*UpdateScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Leaderboard_BatchUpdateScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServer).BatchUpdateScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Leaderboard_BatchUpdateScores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServer).BatchUpdateScores(ctx, req.(*BatchUpdateScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Leaderboard_GetPlayerRank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlayerRankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServer).GetPlayerRank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Leaderboard_GetPlayerRank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServer).GetPlayerRank(ctx, req.(*GetPlayerRankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Leaderboard_GetTopN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServer).GetTopN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Leaderboard_GetTopN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServer).GetTopN(ctx, req.(*GetTopNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Leaderboard_GetPlayerRankRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlayerRankRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServer).GetPlayerRankRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Leaderboard_GetPlayerRankRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServer).GetPlayerRankRange(ctx, req.(*GetPlayerRankRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Leaderboard_WatchTopN_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTopNRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LeaderboardServer).WatchTopN(m, &leaderboardWatchTopNServer{stream})
+}
+
+type Leaderboard_WatchTopNServer interface {
+	Send(*WatchTopNResponse) error
+	grpc.ServerStream
+}
+
+type leaderboardWatchTopNServer struct {
+	grpc.ServerStream
+}
+
+func (x *leaderboardWatchTopNServer) Send(m *WatchTopNResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Leaderboard_ServiceDesc is the grpc.ServiceDesc for Leaderboard service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Leaderboard_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "leaderboard.v1.Leaderboard",
+	HandlerType: (*LeaderboardServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpdateScore",
+			Handler:    _Leaderboard_UpdateScore_Handler,
+		},
+		{
+			MethodName: "BatchUpdateScores",
+			Handler:    _Leaderboard_BatchUpdateScores_Handler,
+		},
+		{
+			MethodName: "GetPlayerRank",
+			Handler:    _Leaderboard_GetPlayerRank_Handler,
+		},
+		{
+			MethodName: "GetTopN",
+			Handler:    _Leaderboard_GetTopN_Handler,
+		},
+		{
+			MethodName: "GetPlayerRankRange",
+			Handler:    _Leaderboard_GetPlayerRankRange_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTopN",
+			Handler:       _Leaderboard_WatchTopN_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "leaderboard.proto",
+}
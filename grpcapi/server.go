@@ -0,0 +1,227 @@
+// Package grpcapi 以 gRPC 接口对外提供 LeaderboardService，协议定义见 leaderboardpb/leaderboard.proto
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"game/grpcapi/leaderboardpb"
+	"game/internal/notify"
+	"game/leaderboard"
+)
+
+const (
+	MaxN           = 1000 // 单次查询或订阅最多返回的名次数
+	MaxBatchSize   = 1000 // 批量更新最多包含的更新数
+	MaxPlayerIDLen = 64   // 玩家ID的最大长度
+)
+
+// Server 实现 leaderboardpb.LeaderboardServer，可以包装任意 LeaderboardService 实现
+type Server struct {
+	leaderboardpb.UnimplementedLeaderboardServer
+
+	board        leaderboard.LeaderboardService
+	now          func() time.Time  // 请求未携带时间戳时使用的时钟
+	pollInterval time.Duration     // 订阅者检查前 N 名变化的间隔，用于发现不经过本服务的写入
+	changes      *notify.Broadcast // 经过本服务的写入完成后立即唤醒订阅者
+}
+
+// NewServer 创建排行榜 gRPC 服务
+func NewServer(board leaderboard.LeaderboardService) *Server {
+	return &Server{
+		board:        board,
+		now:          time.Now,
+		pollInterval: time.Second,
+		changes:      notify.NewBroadcast(),
+	}
+}
+
+// Notify 通知排行榜发生了不经过本服务的写入，所有订阅立即检查前 N 名是否变化
+func (s *Server) Notify() {
+	s.changes.Notify()
+}
+
+// UpdateScore 更新玩家分数，排行榜已满导致新玩家未能上榜时返回 ResourceExhausted，写操作失败时返回 Unavailable
+func (s *Server) UpdateScore(ctx context.Context, req *leaderboardpb.UpdateScoreRequest) (*leaderboardpb.UpdateScoreResponse, error) {
+	if err := validUpdate(req); err != nil {
+		return nil, err
+	}
+	defer s.changes.Notify()
+
+	resp, ok, err := s.update(ctx, req)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "update failed: %v", err)
+	}
+	if !ok {
+		return nil, status.Error(codes.ResourceExhausted, "leaderboard is full")
+	}
+	return resp, nil
+}
+
+// BatchUpdateScores 校验所有更新后按顺序依次执行，任意一条校验失败时不执行任何更新
+// 排行榜已满导致新玩家未能上榜的更新在结果中为空消息，不影响其他更新；
+// 写操作失败时停止执行并返回 Unavailable，之前的更新已经生效
+func (s *Server) BatchUpdateScores(ctx context.Context, req *leaderboardpb.BatchUpdateScoresRequest) (*leaderboardpb.BatchUpdateScoresResponse, error) {
+	if len(req.Updates) > MaxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch must not exceed %d updates", MaxBatchSize)
+	}
+	for i, update := range req.Updates {
+		if err := validUpdate(update); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "updates[%d]: %s", i, status.Convert(err).Message())
+		}
+	}
+	defer s.changes.Notify()
+
+	resp := &leaderboardpb.BatchUpdateScoresResponse{Results: make([]*leaderboardpb.UpdateScoreResponse, 0, len(req.Updates))}
+	for i, update := range req.Updates {
+		res, _, err := s.update(ctx, update)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "updates[%d]: update failed: %v", i, err)
+		}
+		resp.Results = append(resp.Results, res)
+	}
+	return resp, nil
+}
+
+// update 执行一条更新，排行榜已满导致新玩家未能上榜时返回空消息和 false，写操作失败时返回错误
+func (s *Server) update(ctx context.Context, req *leaderboardpb.UpdateScoreRequest) (*leaderboardpb.UpdateScoreResponse, bool, error) {
+	timestamp := s.now()
+	if req.Timestamp != nil {
+		timestamp = req.Timestamp.AsTime()
+	}
+	res, err := leaderboard.UpdateScoreContext(ctx, s.board, req.PlayerId, int(req.Score), timestamp)
+	if err != nil {
+		return nil, false, err
+	}
+	if res.Rejected {
+		return &leaderboardpb.UpdateScoreResponse{}, false, nil
+	}
+	resp := &leaderboardpb.UpdateScoreResponse{
+		New:          toProto(res.New),
+		ScoreChanged: res.ScoreChanged,
+		RankChanged:  res.RankChanged,
+	}
+	if res.Existed {
+		resp.Old = toProto(res.Old)
+	}
+	return resp, true, nil
+}
+
+// GetPlayerRank 获取玩家当前排名
+func (s *Server) GetPlayerRank(ctx context.Context, req *leaderboardpb.GetPlayerRankRequest) (*leaderboardpb.GetPlayerRankResponse, error) {
+	if err := validPlayerID(req.PlayerId); err != nil {
+		return nil, err
+	}
+	info, ok := s.board.GetPlayerRank(req.PlayerId)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "player %q not found", req.PlayerId)
+	}
+	return &leaderboardpb.GetPlayerRankResponse{Info: toProto(info)}, nil
+}
+
+// GetTopN 获取排行榜前 N 名
+func (s *Server) GetTopN(ctx context.Context, req *leaderboardpb.GetTopNRequest) (*leaderboardpb.GetTopNResponse, error) {
+	if err := validN(req.N); err != nil {
+		return nil, err
+	}
+	return &leaderboardpb.GetTopNResponse{Players: toProtoList(s.board.GetTopN(int(req.N)))}, nil
+}
+
+// GetPlayerRankRange 获取玩家周边排名
+func (s *Server) GetPlayerRankRange(ctx context.Context, req *leaderboardpb.GetPlayerRankRangeRequest) (*leaderboardpb.GetPlayerRankRangeResponse, error) {
+	if err := validPlayerID(req.PlayerId); err != nil {
+		return nil, err
+	}
+	if req.Range < 0 || req.Range > MaxN {
+		return nil, status.Errorf(codes.InvalidArgument, "range must be between 0 and %d", MaxN)
+	}
+	infos := s.board.GetPlayerRankRange(req.PlayerId, int(req.Range))
+	if infos == nil {
+		return nil, status.Errorf(codes.NotFound, "player %q not found", req.PlayerId)
+	}
+	return &leaderboardpb.GetPlayerRankRangeResponse{Players: toProtoList(infos)}, nil
+}
+
+// WatchTopN 订阅前 N 名的变化
+// 经过本服务的写入或调用 Notify 后立即检查，其他途径的写入最迟在 pollInterval 后发现；
+// 检查间隔内的多次变化只推送最终结果，客户端接收较慢时同样只会收到最新的前 N 名
+func (s *Server) WatchTopN(req *leaderboardpb.WatchTopNRequest, stream leaderboardpb.Leaderboard_WatchTopNServer) error {
+	if err := validN(req.N); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	var last []leaderboard.RankInfo
+	for sent := false; ; sent = true {
+		// 先取通知通道再读取前 N 名，保证读取之后的写入一定能唤醒本次等待
+		changed := s.changes.Wait()
+		top := s.board.GetTopN(int(req.N))
+		if !sent || !equalRankInfos(top, last) {
+			if err := stream.Send(&leaderboardpb.WatchTopNResponse{Players: toProtoList(top)}); err != nil {
+				return err
+			}
+			last = top
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+func validUpdate(req *leaderboardpb.UpdateScoreRequest) error {
+	if err := validPlayerID(req.PlayerId); err != nil {
+		return err
+	}
+	if req.Timestamp != nil {
+		if err := req.Timestamp.CheckValid(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid timestamp: %v", err)
+		}
+	}
+	return nil
+}
+
+func validPlayerID(playerID string) error {
+	if playerID == "" || len(playerID) > MaxPlayerIDLen {
+		return status.Errorf(codes.InvalidArgument, "player_id must be 1 to %d bytes", MaxPlayerIDLen)
+	}
+	return nil
+}
+
+func validN(n int32) error {
+	if n < 1 || n > MaxN {
+		return status.Errorf(codes.InvalidArgument, "n must be between 1 and %d", MaxN)
+	}
+	return nil
+}
+
+func toProto(info leaderboard.RankInfo) *leaderboardpb.RankInfo {
+	return &leaderboardpb.RankInfo{PlayerId: info.PlayerID, Score: int64(info.Score), Rank: int32(info.Rank)}
+}
+
+func toProtoList(infos []leaderboard.RankInfo) []*leaderboardpb.RankInfo {
+	res := make([]*leaderboardpb.RankInfo, 0, len(infos))
+	for _, info := range infos {
+		res = append(res, toProto(info))
+	}
+	return res
+}
+
+func equalRankInfos(a, b []leaderboard.RankInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"game/grpcapi/leaderboardpb"
+	"game/leaderboard"
+)
+
+// startServer 在内存连接上启动 gRPC 服务并返回客户端
+func startServer(t *testing.T, board leaderboard.LeaderboardService) (leaderboardpb.LeaderboardClient, *Server) {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	srv := NewServer(board)
+	gs := grpc.NewServer()
+	leaderboardpb.RegisterLeaderboardServer(gs, srv)
+	go gs.Serve(ln)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return leaderboardpb.NewLeaderboardClient(conn), srv
+}
+
+func update(id string, score int64, sec int64) *leaderboardpb.UpdateScoreRequest {
+	return &leaderboardpb.UpdateScoreRequest{PlayerId: id, Score: score, Timestamp: timestamppb.New(time.Unix(sec, 0))}
+}
+
+// joinIDs 把玩家ID按名次顺序拼接为逗号分隔的字符串，便于比较
+func joinIDs(infos []*leaderboardpb.RankInfo) string {
+	var ids []string
+	for _, info := range infos {
+		ids = append(ids, info.PlayerId)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestServer(t *testing.T) {
+	for name, newBoard := range map[string]func() leaderboard.LeaderboardService{
+		"LinkedList": func() leaderboard.LeaderboardService { return leaderboard.NewLeaderboardLinkedList() },
+		"SkipList":   func() leaderboard.LeaderboardService { return leaderboard.NewLeaderboardSkipList() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			client, _ := startServer(t, newBoard())
+			ctx := context.Background()
+
+			batch, err := client.BatchUpdateScores(ctx, &leaderboardpb.BatchUpdateScoresRequest{Updates: []*leaderboardpb.UpdateScoreRequest{
+				update("A", 100, 1), update("B", 90, 2), update("C", 90, 3), update("D", 80, 4),
+			}})
+			if err != nil || len(batch.Results) != 4 || batch.Results[2].New.Rank != 3 {
+				t.Fatalf("BatchUpdateScores: %v, %v", batch, err)
+			}
+
+			res, err := client.UpdateScore(ctx, update("D", 95, 5))
+			if err != nil || res.Old.GetRank() != 4 || res.New.GetRank() != 2 || !res.RankChanged {
+				t.Fatalf("UpdateScore: %v, %v", res, err)
+			}
+
+			rank, err := client.GetPlayerRank(ctx, &leaderboardpb.GetPlayerRankRequest{PlayerId: "C"})
+			if err != nil || rank.Info.Rank != 4 || rank.Info.Score != 90 {
+				t.Errorf("GetPlayerRank: %v, %v", rank, err)
+			}
+
+			top, err := client.GetTopN(ctx, &leaderboardpb.GetTopNRequest{N: 2})
+			if err != nil || joinIDs(top.Players) != "A,D" {
+				t.Errorf("GetTopN: %v, %v", top, err)
+			}
+
+			around, err := client.GetPlayerRankRange(ctx, &leaderboardpb.GetPlayerRankRangeRequest{PlayerId: "B", Range: 1})
+			if err != nil || joinIDs(around.Players) != "D,B,C" {
+				t.Errorf("GetPlayerRankRange: %v, %v", around, err)
+			}
+		})
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	client, _ := startServer(t, leaderboard.NewLeaderboardSkipList())
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"missing player", func() error {
+			_, err := client.GetPlayerRank(ctx, &leaderboardpb.GetPlayerRankRequest{PlayerId: "X"})
+			return err
+		}, codes.NotFound},
+		{"empty player id", func() error {
+			_, err := client.UpdateScore(ctx, &leaderboardpb.UpdateScoreRequest{Score: 1})
+			return err
+		}, codes.InvalidArgument},
+		{"n out of range", func() error {
+			_, err := client.GetTopN(ctx, &leaderboardpb.GetTopNRequest{N: 0})
+			return err
+		}, codes.InvalidArgument},
+		{"bad batch", func() error {
+			_, err := client.BatchUpdateScores(ctx, &leaderboardpb.BatchUpdateScoresRequest{Updates: []*leaderboardpb.UpdateScoreRequest{
+				update("A", 1, 1), {PlayerId: ""},
+			}})
+			return err
+		}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		if code := status.Code(tt.call()); code != tt.code {
+			t.Errorf("%s: got %v, want %v", tt.name, code, tt.code)
+		}
+	}
+
+	// 校验失败的批量更新不执行任何更新
+	if _, err := client.GetPlayerRank(ctx, &leaderboardpb.GetPlayerRankRequest{PlayerId: "A"}); status.Code(err) != codes.NotFound {
+		t.Errorf("bad batch was partially applied: %v", err)
+	}
+}
+
+func TestWatchTopN(t *testing.T) {
+	board := leaderboard.NewLeaderboardSkipList()
+	client, srv := startServer(t, board)
+	srv.pollInterval = 20 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchTopN(ctx, &leaderboardpb.WatchTopNRequest{N: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 轮询可能在批量更新执行到一半时推送中间结果，因此读取到期望的前 N 名为止
+	waitFor := func(want string) {
+		t.Helper()
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("waiting for %q: %v", want, err)
+			}
+			if joinIDs(msg.Players) == want {
+				return
+			}
+		}
+	}
+
+	msg, err := stream.Recv()
+	if err != nil || len(msg.Players) != 0 {
+		t.Fatalf("initial top: got %v, %v; want empty", msg, err)
+	}
+	client.BatchUpdateScores(ctx, &leaderboardpb.BatchUpdateScoresRequest{Updates: []*leaderboardpb.UpdateScoreRequest{
+		update("A", 100, 1), update("B", 90, 2),
+	}})
+	waitFor("A,B")
+
+	// 不影响前 N 名的写入不推送
+	client.UpdateScore(ctx, update("C", 10, 3))
+	// 不经过 gRPC 服务的写入通过轮询发现
+	board.UpdateScore("D", 95, time.Unix(4, 0))
+	msg, err = stream.Recv()
+	if err != nil || joinIDs(msg.Players) != "A,D" {
+		t.Fatalf("after direct write: got %v, %v; want A,D", msg, err)
+	}
+}
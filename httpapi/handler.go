@@ -0,0 +1,312 @@
+// Package httpapi 以 HTTP/JSON 接口对外提供 LeaderboardService
+//
+// 接口列表（所有响应均为 JSON，排名信息使用 leaderboard.RankInfo 的 JSON 标签）：
+//
+//	POST /players/{playerId}/score          更新分数，请求体 {"score": 100, "timestamp": "2024-01-01T00:00:00Z"}，timestamp 可省略
+//	GET  /players/{playerId}/rank           获取玩家排名
+//	GET  /players/{playerId}/range?n=5      获取玩家前后各 n 名的排名
+//	GET  /top?n=10                          获取前 n 名
+//	GET  /ranks?start=1&end=50              获取名次区间 [start, end] 内的玩家
+//	GET  /dense/players/{playerId}/rank     获取玩家密集排名
+//	GET  /dense/players/{playerId}/range?n= 获取密集排名前后各 n 名以内的玩家
+//	GET  /dense/top?n=10                    获取前 n 名的密集排名
+//
+// 出错时返回对应的 HTTP 状态码和统一的错误响应体 {"error": {"code": "...", "message": "..."}}
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"game/leaderboard"
+)
+
+const (
+	MaxN            = 1000    // 单次查询最多返回的名次数
+	MaxPlayerIDLen  = 64      // 玩家ID的最大长度
+	maxRequestBytes = 1 << 16 // 请求体的最大字节数
+)
+
+// 错误码
+const (
+	CodeBadRequest       = "bad_request"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeNotImplemented   = "not_implemented"
+	CodeBoardFull        = "board_full"
+	CodeUnavailable      = "unavailable"
+)
+
+// Handler 将 LeaderboardService 暴露为 HTTP/JSON 接口
+type Handler struct {
+	board leaderboard.LeaderboardService
+	now   func() time.Time // 请求未携带时间戳时使用的时钟
+}
+
+// NewHandler 创建排行榜 HTTP 处理器
+func NewHandler(board leaderboard.LeaderboardService) *Handler {
+	return &Handler{board: board, now: time.Now}
+}
+
+// UpdateRequest 更新分数的请求体
+type UpdateRequest struct {
+	Score     *int       `json:"score"`               // 新分数，必填
+	Timestamp *time.Time `json:"timestamp,omitempty"` // 得分时间戳，省略时使用服务器当前时间
+}
+
+// UpdateResponse 更新分数的响应体
+type UpdateResponse struct {
+	Old          *leaderboard.RankInfo `json:"old,omitempty"` // 更新前的排名，玩家原本不在榜上时省略
+	New          leaderboard.RankInfo  `json:"new"`           // 更新后的排名
+	ScoreChanged bool                  `json:"scoreChanged"`  // 分数是否发生变化
+	RankChanged  bool                  `json:"rankChanged"`   // 名次是否发生变化
+}
+
+// ErrorBody 统一的错误响应体
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail 错误详情
+type ErrorDetail struct {
+	Code    string `json:"code"`    // 错误码
+	Message string `json:"message"` // 错误描述
+}
+
+// apiError 处理请求时产生的错误，携带 HTTP 状态码和错误码
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusBadRequest, CodeBadRequest, fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusNotFound, CodeNotFound, fmt.Sprintf(format, args...)}
+}
+
+// ServeHTTP 按路径分发请求
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.route(w, r)
+	if err != nil {
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) {
+			apiErr = &apiError{http.StatusInternalServerError, "internal", err.Error()}
+		}
+		writeJSON(w, apiErr.status, ErrorBody{ErrorDetail{apiErr.code, apiErr.message}})
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// route 解析路径并调用对应的处理函数，返回需要写出的响应
+func (h *Handler) route(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	dense := len(parts) > 0 && parts[0] == "dense"
+	if dense {
+		parts = parts[1:]
+	}
+
+	switch {
+	case len(parts) == 1 && parts[0] == "top":
+		if err := allowMethod(w, r, http.MethodGet); err != nil {
+			return nil, err
+		}
+		return h.topN(r, dense)
+	case len(parts) == 1 && parts[0] == "ranks" && !dense:
+		if err := allowMethod(w, r, http.MethodGet); err != nil {
+			return nil, err
+		}
+		return h.rankRange(r)
+	case len(parts) == 3 && parts[0] == "players":
+		playerID, err := validPlayerID(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case parts[2] == "score" && !dense:
+			if err := allowMethod(w, r, http.MethodPost); err != nil {
+				return nil, err
+			}
+			return h.updateScore(r, playerID)
+		case parts[2] == "rank":
+			if err := allowMethod(w, r, http.MethodGet); err != nil {
+				return nil, err
+			}
+			return h.playerRank(playerID, dense)
+		case parts[2] == "range":
+			if err := allowMethod(w, r, http.MethodGet); err != nil {
+				return nil, err
+			}
+			return h.playerRange(r, playerID, dense)
+		}
+	}
+	return nil, notFound("no route for %s", r.URL.Path)
+}
+
+func (h *Handler) updateScore(r *http.Request, playerID string) (interface{}, error) {
+	var req UpdateRequest
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return nil, badRequest("invalid request body: %v", err)
+	}
+	if req.Score == nil {
+		return nil, badRequest("score is required")
+	}
+	timestamp := h.now()
+	if req.Timestamp != nil {
+		timestamp = *req.Timestamp
+	}
+
+	res, err := leaderboard.UpdateScoreContext(r.Context(), h.board, playerID, *req.Score, timestamp)
+	if err != nil {
+		return nil, &apiError{http.StatusServiceUnavailable, CodeUnavailable, fmt.Sprintf("update failed: %v", err)}
+	}
+	if res.Rejected {
+		return nil, &apiError{http.StatusConflict, CodeBoardFull, "leaderboard is full"}
+	}
+	resp := UpdateResponse{New: res.New, ScoreChanged: res.ScoreChanged, RankChanged: res.RankChanged}
+	if res.Existed {
+		resp.Old = &res.Old
+	}
+	return resp, nil
+}
+
+func (h *Handler) playerRank(playerID string, dense bool) (interface{}, error) {
+	if dense {
+		ranker, err := h.denseRanker()
+		if err != nil {
+			return nil, err
+		}
+		if info := ranker.GetDensePlayerRank(playerID); info.PlayerID != "" {
+			return info, nil
+		}
+	} else if info, ok := h.board.GetPlayerRank(playerID); ok {
+		return info, nil
+	}
+	return nil, notFound("player %q not found", playerID)
+}
+
+func (h *Handler) playerRange(r *http.Request, playerID string, dense bool) (interface{}, error) {
+	n, err := intParam(r, "n", 5, 0, MaxN)
+	if err != nil {
+		return nil, err
+	}
+	var res []leaderboard.RankInfo
+	if dense {
+		ranker, err := h.denseRanker()
+		if err != nil {
+			return nil, err
+		}
+		res = ranker.GetDensePlayerRankRange(playerID, n)
+	} else {
+		res = h.board.GetPlayerRankRange(playerID, n)
+	}
+	if res == nil {
+		return nil, notFound("player %q not found", playerID)
+	}
+	return res, nil
+}
+
+func (h *Handler) topN(r *http.Request, dense bool) (interface{}, error) {
+	n, err := intParam(r, "n", 10, 1, MaxN)
+	if err != nil {
+		return nil, err
+	}
+	if dense {
+		ranker, err := h.denseRanker()
+		if err != nil {
+			return nil, err
+		}
+		return nonNil(ranker.GetDenseTopN(n)), nil
+	}
+	return nonNil(h.board.GetTopN(n)), nil
+}
+
+func (h *Handler) rankRange(r *http.Request) (interface{}, error) {
+	start, err := intParam(r, "start", 1, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	end, err := intParam(r, "end", start+9, start, 0)
+	if err != nil {
+		return nil, err
+	}
+	if end-start+1 > MaxN {
+		return nil, badRequest("rank range must not exceed %d ranks", MaxN)
+	}
+	return nonNil(h.board.GetRankRange(start, end)), nil
+}
+
+// denseRanker 返回支持密集排名查询的排行榜，不支持时返回 501 错误
+func (h *Handler) denseRanker() (leaderboard.DenseRanker, error) {
+	ranker, ok := h.board.(leaderboard.DenseRanker)
+	if !ok {
+		return nil, &apiError{http.StatusNotImplemented, CodeNotImplemented, "dense ranking is not supported by this board"}
+	}
+	return ranker, nil
+}
+
+// allowMethod 检查请求方法，不匹配时设置 Allow 响应头并返回 405 错误
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) error {
+	if r.Method == method || (method == http.MethodGet && r.Method == http.MethodHead) {
+		return nil
+	}
+	w.Header().Set("Allow", method)
+	return &apiError{http.StatusMethodNotAllowed, CodeMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method)}
+}
+
+// validPlayerID 校验路径中的玩家ID
+func validPlayerID(playerID string) (string, error) {
+	if playerID == "" || len(playerID) > MaxPlayerIDLen {
+		return "", badRequest("playerId must be 1 to %d bytes", MaxPlayerIDLen)
+	}
+	return playerID, nil
+}
+
+// intParam 读取整数查询参数，缺省时返回 def；upper 为 0 表示不限制上界
+func intParam(r *http.Request, name string, def, lower, upper int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, badRequest("%s must be an integer", name)
+	}
+	if v < lower || (upper > 0 && v > upper) {
+		if upper > 0 {
+			return 0, badRequest("%s must be between %d and %d", name, lower, upper)
+		}
+		return 0, badRequest("%s must be at least %d", name, lower)
+	}
+	return v, nil
+}
+
+// nonNil 保证空列表序列化为 [] 而不是 null
+func nonNil(infos []leaderboard.RankInfo) []leaderboard.RankInfo {
+	if infos == nil {
+		return []leaderboard.RankInfo{}
+	}
+	return infos
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
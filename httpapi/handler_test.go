@@ -0,0 +1,174 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"game/leaderboard"
+	"game/persist"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	board := leaderboard.NewLeaderboardSkipList()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, s := range []struct {
+		id    string
+		score int
+	}{{"A", 100}, {"B", 90}, {"C", 90}, {"D", 80}} {
+		board.UpdateScore(s.id, s.score, base.Add(time.Duration(i)*time.Second))
+	}
+	srv := httptest.NewServer(NewHandler(board))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// do 发送请求并解码响应体，返回状态码
+func do(t *testing.T, method, url, body string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Fatalf("%s %s: Content-Type = %q", method, url, ct)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decode response: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestHandlerQueries(t *testing.T) {
+	srv := newTestServer(t)
+
+	var info leaderboard.RankInfo
+	if code := do(t, "GET", srv.URL+"/players/C/rank", "", &info); code != http.StatusOK || info != (leaderboard.RankInfo{PlayerID: "C", Score: 90, Rank: 3}) {
+		t.Errorf("rank: code %d, got %+v", code, info)
+	}
+	if code := do(t, "GET", srv.URL+"/dense/players/C/rank", "", &info); code != http.StatusOK || info.Rank != 2 {
+		t.Errorf("dense rank: code %d, got %+v", code, info)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/top?n=2", []string{"A", "B"}},
+		{"/ranks?start=2&end=3", []string{"B", "C"}},
+		{"/ranks?start=10&end=20", []string{}},
+		{"/players/C/range?n=1", []string{"B", "C", "D"}},
+		{"/dense/top?n=2", []string{"A", "B"}},
+		{"/dense/players/A/range?n=1", []string{"A", "B", "C"}},
+	}
+	for _, tt := range tests {
+		var infos []leaderboard.RankInfo
+		if code := do(t, "GET", srv.URL+tt.path, "", &infos); code != http.StatusOK {
+			t.Errorf("%s: code %d", tt.path, code)
+			continue
+		}
+		if infos == nil {
+			t.Errorf("%s: got null, want a list", tt.path)
+		}
+		var got []string
+		for _, info := range infos {
+			got = append(got, info.PlayerID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestHandlerUpdateScore(t *testing.T) {
+	srv := newTestServer(t)
+
+	var resp UpdateResponse
+	code := do(t, "POST", srv.URL+"/players/D/score", `{"score":95,"timestamp":"2024-01-02T00:00:00Z"}`, &resp)
+	if code != http.StatusOK || resp.Old == nil || resp.Old.Rank != 4 || resp.New.Rank != 2 || !resp.RankChanged {
+		t.Fatalf("update D: code %d, got %+v", code, resp)
+	}
+
+	resp = UpdateResponse{}
+	code = do(t, "POST", srv.URL+"/players/E/score", `{"score":10}`, &resp)
+	if code != http.StatusOK || resp.Old != nil || resp.New != (leaderboard.RankInfo{PlayerID: "E", Score: 10, Rank: 5}) {
+		t.Fatalf("insert E: code %d, got %+v", code, resp)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		method, path, body string
+		code               int
+		errCode            string
+	}{
+		{"GET", "/players/X/rank", "", http.StatusNotFound, CodeNotFound},
+		{"GET", "/players/X/range", "", http.StatusNotFound, CodeNotFound},
+		{"GET", "/unknown", "", http.StatusNotFound, CodeNotFound},
+		{"GET", "/top?n=0", "", http.StatusBadRequest, CodeBadRequest},
+		{"GET", "/top?n=abc", "", http.StatusBadRequest, CodeBadRequest},
+		{"GET", "/top?n=1001", "", http.StatusBadRequest, CodeBadRequest},
+		{"GET", "/ranks?start=5&end=2", "", http.StatusBadRequest, CodeBadRequest},
+		{"GET", "/ranks?start=1&end=2000", "", http.StatusBadRequest, CodeBadRequest},
+		{"GET", "/players/" + strings.Repeat("x", MaxPlayerIDLen+1) + "/rank", "", http.StatusBadRequest, CodeBadRequest},
+		{"POST", "/players/A/score", `{}`, http.StatusBadRequest, CodeBadRequest},
+		{"POST", "/players/A/score", `{"score":"1"}`, http.StatusBadRequest, CodeBadRequest},
+		{"POST", "/players/A/score", `{"score":1,"extra":true}`, http.StatusBadRequest, CodeBadRequest},
+		{"GET", "/players/A/score", "", http.StatusMethodNotAllowed, CodeMethodNotAllowed},
+		{"POST", "/top", "", http.StatusMethodNotAllowed, CodeMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		var body ErrorBody
+		code := do(t, tt.method, srv.URL+tt.path, tt.body, &body)
+		if code != tt.code || body.Error.Code != tt.errCode || body.Error.Message == "" {
+			t.Errorf("%s %s: code %d, body %+v; want code %d, error code %s", tt.method, tt.path, code, body, tt.code, tt.errCode)
+		}
+	}
+}
+
+// plainBoard 只实现 LeaderboardService，不支持密集排名查询
+type plainBoard struct {
+	leaderboard.LeaderboardService
+}
+
+func TestHandlerDenseNotImplemented(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(plainBoard{leaderboard.NewLeaderboardLinkedList()}))
+	defer srv.Close()
+
+	var body ErrorBody
+	if code := do(t, "GET", srv.URL+"/dense/top", "", &body); code != http.StatusNotImplemented || body.Error.Code != CodeNotImplemented {
+		t.Errorf("code %d, body %+v", code, body)
+	}
+}
+
+func TestHandlerWriteFailure(t *testing.T) {
+	durable, err := persist.OpenDurable(filepath.Join(t.TempDir(), "board.wal"), leaderboard.NewLeaderboardSkipList(), persist.LogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	durable.Close() // 日志关闭后写操作失败
+	srv := httptest.NewServer(NewHandler(durable))
+	defer srv.Close()
+
+	var body ErrorBody
+	if code := do(t, "POST", srv.URL+"/players/A/score", `{"score":1}`, &body); code != http.StatusServiceUnavailable || body.Error.Code != CodeUnavailable {
+		t.Errorf("code %d, body %+v; want 503 %s", code, body, CodeUnavailable)
+	}
+	if durable.Len() != 0 {
+		t.Errorf("Len() = %d after failed write; want 0", durable.Len())
+	}
+}
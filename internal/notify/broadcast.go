@@ -0,0 +1,34 @@
+// Package notify 提供排行榜各个对外接口共用的变化通知
+package notify
+
+import "sync"
+
+// Broadcast 一对多的变化通知：每次 Notify 关闭当前通道并换上新通道，唤醒所有等待者
+// 等待者只关心“发生过变化”，多次通知会合并为一次唤醒
+type Broadcast struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// NewBroadcast 创建变化通知
+func NewBroadcast() *Broadcast {
+	return &Broadcast{ch: make(chan struct{})}
+}
+
+// Wait 返回下一次 Notify 时关闭的通道
+// 等待者应先取得通道再读取排行榜，保证读取之后发生的变化一定能唤醒本次等待
+func (b *Broadcast) Wait() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.ch
+}
+
+// Notify 唤醒所有通过 Wait 取得通道的等待者
+func (b *Broadcast) Notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	close(b.ch)
+	b.ch = make(chan struct{})
+}
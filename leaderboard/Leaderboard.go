@@ -1,11 +1,15 @@
 package leaderboard
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // Player 表示玩家信息，包含唯一标识、分数和得分时间戳（用于处理同分情况）
 type Player struct {
 	PlayerID  string    // 玩家唯一ID
-	Score     int       // 玩家当前分数  排名降序
+	Score     int       // 玩家当前分数  默认排名降序
 	Timestamp time.Time // 得分时间戳,时间戳早的靠前
 }
 
@@ -16,11 +20,97 @@ type RankInfo struct {
 	Rank     int    `json:"rank"`     // 玩家排名（从1开始）
 }
 
+// ErrUnsorted 批量装载的玩家没有按排行榜的排序规则排列
+var ErrUnsorted = errors.New("leaderboard: players are not in rank order")
+
+// ErrBoardFull 批量装载的玩家数超过排行榜的玩家数上限
+var ErrBoardFull = errors.New("leaderboard: too many players")
+
+// UpdateResult 表示一次分数更新的结果，调用方无需再调用 GetPlayerRank 即可知道更新效果
+type UpdateResult struct {
+	Old          RankInfo // 更新前的排名信息，玩家原本不在榜上时为空
+	New          RankInfo // 更新后的排名信息
+	Existed      bool     // 更新前玩家是否已在榜上
+	ScoreChanged bool     // 分数是否发生变化，新玩家上榜视为变化
+	RankChanged  bool     // 名次是否发生变化，新玩家上榜视为变化
+	Rejected     bool     // 排行榜已达到玩家数上限，新玩家未能上榜，此时其他字段均为空
+	Err          error    // 写操作失败的原因，例如预写日志写入失败，此时其他字段均为空，见 ContextWriter
+}
+
 type LeaderboardService interface {
-	UpdateScore(playerID string, score int, timestamp time.Time) // 更新分数
-	GetPlayerRank(playerID string) (RankInfo, bool)              // 获取个人排名
-	GetTopN(n int) []RankInfo                                    // 获取前N名
-	GetPlayerRankRange(playerID string, rangeN int) []RankInfo   // 获取周边排名
+	UpdateScore(playerID string, score int, timestamp time.Time) UpdateResult // 按更新策略更新分数
+	GetPlayerRank(playerID string) (RankInfo, bool)                           // 获取个人排名
+	GetTopN(n int) []RankInfo                                                 // 获取前N名
+	GetPlayerRankRange(playerID string, rangeN int) []RankInfo                // 获取周边排名
+
+	GetByRank(rank int) (RankInfo, bool)    // 获取指定名次的玩家
+	GetRankRange(start, end int) []RankInfo // 获取名次区间 [start, end] 内的玩家
+	Len() int                               // 获取排行榜玩家总数
+
+	RemovePlayer(playerID string) bool     // 删除玩家，玩家不存在时返回 false
+	RemovePlayers(playerIDs ...string) int // 批量删除玩家，返回实际删除的数量
+	Reset()                                // 清空排行榜
+
+	IncrementScore(playerID string, delta int, timestamp time.Time) RankInfo // 原子地增加分数并返回新的排名，新玩家因排行榜已满未能上榜或写操作失败时返回空
+
+	CountByScore(r ScoreRange) int                         // 统计分数区间内的玩家数
+	GetByScore(r ScoreRange, offset, limit int) []RankInfo // 按名次顺序获取分数区间内的玩家，limit 小于等于 0 表示不限制
+
+	GetPlayerPercentile(playerID string) (float64, bool) // 获取玩家击败的百分比，取值 [0, 100)
+	GetTopPercent(p float64) []RankInfo                  // 获取名次位于前 p% 的玩家
+
+	ForEach(fn func(p Player) bool) // 按名次顺序遍历玩家，fn 返回 false 时停止；fn 中不能调用排行榜的方法
+}
+
+// LeaderboardReader LeaderboardService 中的只读查询，用于只读的排行榜，例如赛季归档和从副本
+type LeaderboardReader interface {
+	GetPlayerRank(playerID string) (RankInfo, bool)
+	GetTopN(n int) []RankInfo
+	GetPlayerRankRange(playerID string, rangeN int) []RankInfo
+	GetByRank(rank int) (RankInfo, bool)
+	GetRankRange(start, end int) []RankInfo
+	Len() int
+	CountByScore(r ScoreRange) int
+	GetByScore(r ScoreRange, offset, limit int) []RankInfo
+	GetPlayerPercentile(playerID string) (float64, bool)
+	GetTopPercent(p float64) []RankInfo
+	ForEach(fn func(p Player) bool)
+}
+
+// BulkLoader 支持批量装载的排行榜
+// Load 用已按排行榜排序规则排好序的玩家替换排行榜的全部内容，时间复杂度 O(n)；
+// 玩家未按顺序排列或玩家ID重复时返回错误，排行榜内容保持不变
+type BulkLoader interface {
+	Load(players []Player) error
+}
+
+// newUpdateResult 根据更新前后的排名信息生成更新结果
+func newUpdateResult(old RankInfo, existed bool, updated RankInfo) UpdateResult {
+	return UpdateResult{
+		Old:          old,
+		New:          updated,
+		Existed:      existed,
+		ScoreChanged: !existed || old.Score != updated.Score,
+		RankChanged:  !existed || old.Rank != updated.Rank,
+	}
+}
+
+// checkSorted 检查玩家是否严格按排序规则排列、玩家ID不重复且玩家数不超过上限
+func (o *options) checkSorted(players []Player) error {
+	if o.exceeds(len(players)) {
+		return fmt.Errorf("%w: %d players exceed the limit of %d", ErrBoardFull, len(players), o.maxPlayers)
+	}
+	seen := make(map[string]struct{}, len(players))
+	for i := range players {
+		if _, dup := seen[players[i].PlayerID]; dup {
+			return fmt.Errorf("leaderboard: duplicate player %q", players[i].PlayerID)
+		}
+		seen[players[i].PlayerID] = struct{}{}
+		if i > 0 && !o.before(&players[i-1], &players[i]) {
+			return fmt.Errorf("%w: %q at index %d", ErrUnsorted, players[i].PlayerID, i)
+		}
+	}
+	return nil
 }
 
 // max 函数用于返回两个整数中的较大值
@@ -0,0 +1,22 @@
+package leaderboard_test
+
+import (
+	"testing"
+	"time"
+
+	"game/leaderboard"
+	"game/leaderboard/leaderboardtest"
+)
+
+// leaderboardtest 依赖 leaderboard，一致性测试放在外部测试包中以避免循环引用
+
+func TestSeasonalBoard_Conformance(t *testing.T) {
+	// 时钟固定，测试期间不会切换赛季，所有操作作用于同一个赛季
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	leaderboardtest.Test(t, func(opts ...leaderboard.Option) leaderboard.LeaderboardService {
+		return leaderboard.NewSeasonalBoard(leaderboard.SeasonConfig{
+			Schedule: leaderboard.Daily(nil),
+			Now:      func() time.Time { return now },
+		}, opts...)
+	}, leaderboardtest.Config{Streams: 1})
+}
@@ -1,37 +1,28 @@
 package leaderboard
 
-// getDenseRanks 计算密集排名
-func (l *LeaderboardLinkedList) getDenseRanks() map[string]int {
-	ranks := make(map[string]int)
-	currentRank := 0
-	prevScore := -1
+// 密集排名查询：无论排行榜配置了哪种排名策略，都按 DenseRanking 计算名次
 
-	for e := l.players.Front(); e != nil; e = e.Next() {
-		p := e.Value.(*Player)
-		if p.Score != prevScore {
-			currentRank = currentRank + 1
-			prevScore = p.Score
-		}
-		ranks[p.PlayerID] = currentRank
-	}
-	return ranks
+// DenseRanker 支持密集排名查询的排行榜
+type DenseRanker interface {
+	GetDensePlayerRank(playerID string) RankInfo                    // 获取玩家密集排名，玩家不存在时返回空的排名信息
+	GetDenseTopN(n int) []RankInfo                                  // 获取前N名的密集排名
+	GetDensePlayerRankRange(playerID string, rangeN int) []RankInfo // 获取密集排名前后各 rangeN 名以内的玩家
 }
 
-// GetDensePlayerRank 获取玩家排名 链表遍历计算
-// 如果玩家存在于排行榜中，返回其排名信息和 true；否则返回空的排名信息和 false
+// GetDensePlayerRank 获取玩家密集排名
+// 如果玩家存在于排行榜中，返回其排名信息；否则返回空的排名信息
 func (l *LeaderboardLinkedList) GetDensePlayerRank(playerID string) RankInfo {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
 	// 检查玩家是否存在于排行榜中
 	if elem, exists := l.playerMap[playerID]; exists {
-		ranks := l.getDenseRanks()
 		p := elem.Value.(*Player)
 
 		return RankInfo{
 			PlayerID: playerID,
 			Score:    p.Score,
-			Rank:     ranks[playerID],
+			Rank:     DenseRanking.Rank(linkedListCounter{l}, l.rankOf(elem), p.Score),
 		}
 	}
 
@@ -44,43 +35,107 @@ func (l *LeaderboardLinkedList) GetDenseTopN(n int) []RankInfo {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
-	ranks := l.getDenseRanks()
 	var res []RankInfo
 	i := 0
 	// 遍历链表，获取前 N 名玩家信息
 	for e := l.players.Front(); e != nil && i < n; e = e.Next() {
 		p := e.Value.(*Player)
-		res = append(res, RankInfo{p.PlayerID, p.Score, ranks[p.PlayerID]})
+		res = append(res, RankInfo{p.PlayerID, p.Score, i + 1})
 		i++
 	}
-	return res
+	return applyRanking(DenseRanking, linkedListCounter{l}, res)
 }
 
-// GetDensePlayerRankRange 获取周边排名（链表二次遍历）
-// 返回指定玩家前后各 rangeN 名玩家的排名信息
+// GetDensePlayerRankRange 获取周边排名（链表遍历）
+// 返回密集排名在指定玩家密集排名前后各 rangeN 名以内的所有玩家
 func (l *LeaderboardLinkedList) GetDensePlayerRankRange(playerID string, rangeN int) []RankInfo {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 	// 检查玩家是否存在于排行榜中
-	if _, exists := l.playerMap[playerID]; exists {
-		ranks := l.getDenseRanks()
-		currentRank := ranks[playerID]
+	if elem, exists := l.playerMap[playerID]; exists {
+		currentRank := DenseRanking.Rank(linkedListCounter{l}, 0, elem.Value.(*Player).Score)
 
 		start := max(1, currentRank-rangeN)             // 计算排名范围的起始位置
 		end := min(l.players.Len(), currentRank+rangeN) // 计算排名范围的结束位置
 
 		var res []RankInfo
-		rankCount := 0
-		// 遍历链表，获取排名范围内的玩家信息
+		playerRank := 0
+		// 遍历链表，遇到新的分数时密集排名加 1，获取排名范围内的玩家信息
 		for e := l.players.Front(); e != nil; e = e.Next() {
 			p := e.Value.(*Player)
-			playerRank := ranks[p.PlayerID]
-			if playerRank >= start && playerRank <= end {
+			if prev := e.Prev(); prev == nil || prev.Value.(*Player).Score != p.Score {
+				playerRank++
+			}
+			if playerRank > end {
+				break
+			}
+			if playerRank >= start {
 				res = append(res, RankInfo{p.PlayerID, p.Score, playerRank})
-				rankCount++
 			}
 		}
 		return res
 	}
 	return nil
 }
+
+// GetDensePlayerRank 获取玩家密集排名（不同分数索引，O(log n)）
+// 如果玩家存在于排行榜中，返回其排名信息；否则返回空的排名信息
+func (l *LeaderboardSkipList) GetDensePlayerRank(playerID string) RankInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if node, exists := l.playerMap[playerID]; exists {
+		return RankInfo{
+			PlayerID: playerID,
+			Score:    node.score,
+			Rank:     DenseRanking.Rank(skipListCounter{l}, 0, node.score),
+		}
+	}
+	return RankInfo{}
+}
+
+// GetDenseTopN 获取TopN（跳表底层遍历）
+func (l *LeaderboardSkipList) GetDenseTopN(n int) []RankInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var res []RankInfo
+	current := l.header.forward[0]
+	for i := 0; current != nil && i < n; i++ {
+		res = append(res, RankInfo{current.player.PlayerID, current.score, i + 1})
+		current = current.forward[0]
+	}
+	return applyRanking(DenseRanking, skipListCounter{l}, res)
+}
+
+// GetDensePlayerRankRange 获取周边排名（索引定位起始分数+底层遍历）
+// 返回密集排名在指定玩家密集排名前后各 rangeN 名以内的所有玩家
+func (l *LeaderboardSkipList) GetDensePlayerRankRange(playerID string, rangeN int) []RankInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if node, exists := l.playerMap[playerID]; exists {
+		currentRank := l.scores.countBetter(node.score) + 1
+
+		start := max(1, currentRank-rangeN)                 // 计算排名范围的起始位置
+		end := min(l.getTotalPlayers(), currentRank+rangeN) // 计算排名范围的结束位置
+
+		// 第 start 个不同分数的第一名玩家即为起始节点
+		startScore, _ := l.scores.scoreByRank(start)
+		current := l.getNodeByRank(skipListCounter{l}.CountBetter(startScore) + 1)
+
+		var res []RankInfo
+		playerRank := start
+		for ; current != nil; current = current.forward[0] {
+			if len(res) > 0 && res[len(res)-1].Score != current.score {
+				playerRank++
+			}
+			if playerRank > end {
+				break
+			}
+			res = append(res, RankInfo{current.player.PlayerID, current.score, playerRank})
+		}
+		return res
+	}
+	return nil
+}
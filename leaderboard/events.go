@@ -0,0 +1,287 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// EventType 排名变化事件的类型
+type EventType int
+
+const (
+	EventRankChanged  EventType = iota // 更新的玩家名次发生变化，包括新上榜（Old 为空）和被删除（New 为空）
+	EventScoreChanged                  // 已在榜上的玩家分数发生变化
+	EventEnteredTopN                   // 玩家进入前 N 名，包括被其他玩家的变化带入前 N 名
+	EventLeftTopN                      // 玩家跌出前 N 名，包括被其他玩家挤出和被删除
+	EventOvertaken                     // 玩家被更新的玩家超越，By 为超越者
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventRankChanged:
+		return "RankChanged"
+	case EventScoreChanged:
+		return "ScoreChanged"
+	case EventEnteredTopN:
+		return "EnteredTopN"
+	case EventLeftTopN:
+		return "LeftTopN"
+	case EventOvertaken:
+		return "Overtaken"
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event 排名变化事件
+type Event struct {
+	Type     EventType
+	PlayerID string   // 事件对应的玩家
+	Old      RankInfo // 变化前的排名信息，变化前不在榜上时为空
+	New      RankInfo // 变化后的排名信息，变化后不在榜上时为空
+	By       string   // EventOvertaken：超越该玩家的玩家ID
+}
+
+// DefaultQueueSize 监听器队列默认最多积压的事件数
+const DefaultQueueSize = 4096
+
+// EventBusConfig 事件总线配置
+type EventBusConfig struct {
+	TopN         int // 进入、跌出前 N 名事件的名次阈值（按序数名次），0 表示不产生这两类事件
+	MaxOvertaken int // 一次更新最多产生的被超越事件数，取紧随更新玩家新名次之后的玩家，0 表示不产生被超越事件
+	QueueSize    int // 每个监听器最多积压的事件数，小于等于 0 时使用 DefaultQueueSize
+}
+
+// EventBus 排名变化事件总线，通过 WithEventBus 挂到排行榜上
+//
+// 排行榜在写锁内计算事件并放入每个监听器各自的队列，再由监听器自己的协程依次回调，
+// 因此回调在排行榜的锁之外执行，慢监听器不会阻塞写入和其他监听器。
+// 监听器的队列积压到 QueueSize 后丢弃新事件，丢弃的事件数可以通过 Dropped 查询，不会为慢监听器无限积压事件。
+// 同一监听器收到的事件顺序与写入顺序一致；没有监听器时排行榜不计算事件。
+//
+// 事件覆盖 UpdateScore、IncrementScore、RemovePlayer、RemovePlayers，Reset 和批量装载不产生事件。
+// 被动的名次变化（被超越、因他人被删除而上升）不产生 EventRankChanged，只通过 EventOvertaken 和前 N 名事件体现
+type EventBus struct {
+	dropped int64 // 因队列已满丢弃的事件数，原子访问，放在首位保证 64 位对齐
+
+	cfg EventBusConfig
+
+	mu        sync.RWMutex
+	listeners map[int]*listener
+	nextID    int
+}
+
+// NewEventBus 创建事件总线
+func NewEventBus(cfg EventBusConfig) *EventBus {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	return &EventBus{cfg: cfg, listeners: make(map[int]*listener)}
+}
+
+// Subscribe 注册监听器，返回取消注册的函数；取消后队列中尚未回调的事件被丢弃
+func (b *EventBus) Subscribe(fn func(Event)) (cancel func()) {
+	ln := &listener{fn: fn, limit: b.cfg.QueueSize, dropped: &b.dropped, wake: make(chan struct{}, 1), done: make(chan struct{})}
+	go ln.run()
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.listeners[id] = ln
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.listeners, id)
+			b.mu.Unlock()
+			close(ln.done)
+		})
+	}
+}
+
+// Dropped 返回所有监听器因队列已满累计丢弃的事件数
+func (b *EventBus) Dropped() int64 {
+	return atomic.LoadInt64(&b.dropped)
+}
+
+// active 判断是否有监听器
+func (b *EventBus) active() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.listeners) > 0
+}
+
+// publish 把事件放入所有监听器的队列，由排行榜在写锁内调用
+func (b *EventBus) publish(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ln := range b.listeners {
+		ln.push(events)
+	}
+}
+
+// listener 一个监听器及其事件队列
+type listener struct {
+	fn      func(Event)
+	limit   int    // 队列最多积压的事件数
+	dropped *int64 // 所属事件总线的丢弃计数
+	mu      sync.Mutex
+	queue   []Event
+	wake    chan struct{} // 队列由空变为非空时唤醒回调协程
+	done    chan struct{}
+}
+
+// push 把事件放入队列，队列放不下的事件被丢弃并计数
+func (ln *listener) push(events []Event) {
+	ln.mu.Lock()
+	if room := ln.limit - len(ln.queue); len(events) > room {
+		atomic.AddInt64(ln.dropped, int64(len(events)-room))
+		events = events[:room]
+	}
+	ln.queue = append(ln.queue, events...)
+	ln.mu.Unlock()
+
+	select {
+	case ln.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run 回调协程，每次取出队列中的全部事件依次回调
+func (ln *listener) run() {
+	for {
+		select {
+		case <-ln.done:
+			return
+		case <-ln.wake:
+		}
+		ln.mu.Lock()
+		events := ln.queue
+		ln.queue = nil
+		ln.mu.Unlock()
+
+		for _, e := range events {
+			select {
+			case <-ln.done:
+				return
+			default:
+			}
+			ln.fn(e)
+		}
+	}
+}
+
+// eventSource 生成事件所需的排行榜内部查询，调用方需持有锁
+type eventSource interface {
+	info(playerID string) (RankInfo, int, bool) // 玩家的排名信息和序数排名
+	infoAt(ordinal int) (RankInfo, bool)        // 指定序数排名的玩家的排名信息
+	countBefore(p *Player) int                  // 除 p 本人外排在 p 之前的玩家数
+	size() int                                  // 玩家总数
+}
+
+// trackedPlayer 写入前记录的玩家排名
+type trackedPlayer struct {
+	old       RankInfo
+	ordinal   int  // 写入前的序数排名，不在榜上时为 0
+	overtaken bool // 是否可能被更新的玩家超越
+}
+
+// changeRecorder 在一次写入前记录相关玩家的排名，写入后与新排名比较生成事件，调用方需持有写锁
+type changeRecorder struct {
+	bus      *EventBus
+	playerID string
+	mover    trackedPlayer
+	tracked  []trackedPlayer // 被超越的候选玩家和前 N 名边界上的玩家，按写入前的名次排列
+}
+
+// begin 在写入前记录排名，next 为写入后的玩家记录，删除玩家时为 nil；没有监听器时返回 nil
+func (b *EventBus) begin(src eventSource, playerID string, next *Player) *changeRecorder {
+	if !b.active() {
+		return nil
+	}
+	rec := &changeRecorder{bus: b, playerID: playerID}
+	old, ordinal, exists := src.info(playerID)
+	if !exists && next == nil {
+		return nil
+	}
+	rec.mover = trackedPlayer{old: old, ordinal: ordinal}
+
+	seen := map[string]bool{playerID: true}
+	track := func(ordinal int, overtaken bool) {
+		info, ok := src.infoAt(ordinal)
+		if !ok || seen[info.PlayerID] {
+			return
+		}
+		seen[info.PlayerID] = true
+		rec.tracked = append(rec.tracked, trackedPlayer{old: info, ordinal: ordinal, overtaken: overtaken})
+	}
+
+	// 写入后排在新位置之后、写入前排在更新玩家之前的玩家被超越，只记录紧随新位置之后的若干名
+	if next != nil && b.cfg.MaxOvertaken > 0 {
+		first := src.countBefore(next) + 1
+		last := src.size()
+		if exists {
+			last = ordinal - 1
+		}
+		for i := first; i <= last && i < first+b.cfg.MaxOvertaken; i++ {
+			track(i, true)
+		}
+	}
+	// 一次写入最多让其他玩家的序数排名移动一位，只有边界两侧的玩家可能进出前 N 名
+	if n := b.cfg.TopN; n > 0 {
+		track(n, false)
+		track(n+1, false)
+	}
+	return rec
+}
+
+// finish 在写入后比较排名，生成事件并放入监听器队列
+func (rec *changeRecorder) finish(src eventSource) {
+	if rec == nil {
+		return
+	}
+	var events []Event
+	topN := rec.bus.cfg.TopN
+	crossTopN := func(id string, p trackedPlayer, cur RankInfo, ordinal int) {
+		if topN <= 0 {
+			return
+		}
+		wasIn := p.ordinal > 0 && p.ordinal <= topN
+		isIn := ordinal > 0 && ordinal <= topN
+		switch {
+		case !wasIn && isIn:
+			events = append(events, Event{Type: EventEnteredTopN, PlayerID: id, Old: p.old, New: cur})
+		case wasIn && !isIn:
+			events = append(events, Event{Type: EventLeftTopN, PlayerID: id, Old: p.old, New: cur})
+		}
+	}
+
+	cur, ordinal, _ := src.info(rec.playerID)
+	if rec.mover.old.Rank != cur.Rank {
+		events = append(events, Event{Type: EventRankChanged, PlayerID: rec.playerID, Old: rec.mover.old, New: cur})
+	}
+	if rec.mover.ordinal > 0 && ordinal > 0 && rec.mover.old.Score != cur.Score {
+		events = append(events, Event{Type: EventScoreChanged, PlayerID: rec.playerID, Old: rec.mover.old, New: cur})
+	}
+	crossTopN(rec.playerID, rec.mover, cur, ordinal)
+
+	for _, p := range rec.tracked {
+		id := p.old.PlayerID
+		info, ord, _ := src.info(id)
+		if p.overtaken && ord > p.ordinal {
+			events = append(events, Event{Type: EventOvertaken, PlayerID: id, Old: p.old, New: info, By: rec.playerID})
+		}
+		crossTopN(id, p, info, ord)
+	}
+	rec.bus.publish(events)
+}
@@ -1,14 +1,25 @@
 package leaderboard
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
 const numPlayers = 1000
 
+// boardConstructors 各排行榜实现的构造函数，用于对所有实现运行同一组测试
+var boardConstructors = map[string]func(opts ...Option) LeaderboardService{
+	"LinkedList": func(opts ...Option) LeaderboardService { return NewLeaderboardLinkedList(opts...) },
+	"SkipList":   func(opts ...Option) LeaderboardService { return NewLeaderboardSkipList(opts...) },
+	"Sharded":    func(opts ...Option) LeaderboardService { return NewShardedBoard(3, opts...) },
+}
+
 func BenchmarkLinkedListUpdateScore(b *testing.B) {
 	lb := NewLeaderboardLinkedList()
 	for i := 0; i < b.N; i++ {
@@ -183,3 +194,1030 @@ func TestLeaderboardLinkedList_DenseRank(t *testing.T) {
 		}
 	}
 }
+
+func TestLeaderboardSkipList_RankMatchesLinkedList(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	linked := NewLeaderboardLinkedList()
+	skip := NewLeaderboardSkipList()
+	base := time.Now()
+
+	// 随机更新玩家分数，分数范围较小以制造大量同分情况
+	for i := 0; i < 5000; i++ {
+		playerID := fmt.Sprintf("player%d", r.Intn(300))
+		score := r.Intn(50)
+		timestamp := base.Add(time.Duration(r.Intn(20)) * time.Second)
+		linked.UpdateScore(playerID, score, timestamp)
+		skip.UpdateScore(playerID, score, timestamp)
+	}
+
+	if skip.getTotalPlayers() != linked.players.Len() {
+		t.Fatalf("getTotalPlayers() = %d; want %d", skip.getTotalPlayers(), linked.players.Len())
+	}
+	for i := 0; i < 300; i++ {
+		playerID := fmt.Sprintf("player%d", i)
+		want, wantOk := linked.GetPlayerRank(playerID)
+		got, gotOk := skip.GetPlayerRank(playerID)
+		if gotOk != wantOk || got != want {
+			t.Fatalf("GetPlayerRank(%s) = %+v, %v; want %+v, %v", playerID, got, gotOk, want, wantOk)
+		}
+		if node := skip.getNodeByRank(got.Rank); gotOk && node.player.PlayerID != playerID {
+			t.Fatalf("getNodeByRank(%d) = %s; want %s", got.Rank, node.player.PlayerID, playerID)
+		}
+		wantRange := linked.GetPlayerRankRange(playerID, 3)
+		gotRange := skip.GetPlayerRankRange(playerID, 3)
+		if fmt.Sprint(gotRange) != fmt.Sprint(wantRange) {
+			t.Fatalf("GetPlayerRankRange(%s, 3) = %+v; want %+v", playerID, gotRange, wantRange)
+		}
+	}
+}
+
+func TestLeaderboard_GetRankRange(t *testing.T) {
+	for name, newBoard := range boardConstructors {
+		lb := newBoard()
+		base := time.Now()
+		for i := 1; i <= 100; i++ {
+			lb.UpdateScore(fmt.Sprintf("player%d", i), i, base)
+		}
+
+		if lb.Len() != 100 {
+			t.Errorf("%s Len() = %d; want 100", name, lb.Len())
+		}
+		if got, ok := lb.GetByRank(1); !ok || got != (RankInfo{"player100", 100, 1}) {
+			t.Errorf("%s GetByRank(1) = %+v, %v", name, got, ok)
+		}
+		if _, ok := lb.GetByRank(101); ok {
+			t.Errorf("%s GetByRank(101) should not exist", name)
+		}
+
+		got := lb.GetRankRange(11, 15)
+		if len(got) != 5 {
+			t.Fatalf("%s GetRankRange(11, 15) length = %d; want 5", name, len(got))
+		}
+		for i, info := range got {
+			if want := (RankInfo{fmt.Sprintf("player%d", 90-i), 90 - i, 11 + i}); info != want {
+				t.Errorf("%s GetRankRange(11, 15)[%d] = %+v; want %+v", name, i, info, want)
+			}
+		}
+		if got := lb.GetRankRange(98, 200); len(got) != 3 || got[2].Rank != 100 {
+			t.Errorf("%s GetRankRange(98, 200) = %+v", name, got)
+		}
+		if got := lb.GetRankRange(20, 10); got != nil {
+			t.Errorf("%s GetRankRange(20, 10) = %+v; want nil", name, got)
+		}
+	}
+}
+
+func TestLeaderboard_RemovePlayer(t *testing.T) {
+	for name, newBoard := range boardConstructors {
+		lb := newBoard()
+		base := time.Now()
+		for i := 1; i <= 10; i++ {
+			lb.UpdateScore(fmt.Sprintf("player%d", i), i*10, base)
+		}
+
+		if !lb.RemovePlayer("player10") {
+			t.Errorf("%s RemovePlayer(player10) = false; want true", name)
+		}
+		if lb.RemovePlayer("player10") {
+			t.Errorf("%s RemovePlayer(player10) twice = true; want false", name)
+		}
+		if got, ok := lb.GetPlayerRank("player9"); !ok || got.Rank != 1 {
+			t.Errorf("%s GetPlayerRank(player9) = %+v, %v; want rank 1", name, got, ok)
+		}
+		if n := lb.RemovePlayers("player1", "player2", "unknown"); n != 2 {
+			t.Errorf("%s RemovePlayers() = %d; want 2", name, n)
+		}
+		if lb.Len() != 7 {
+			t.Errorf("%s Len() = %d; want 7", name, lb.Len())
+		}
+		if got, ok := lb.GetByRank(7); !ok || got.PlayerID != "player3" {
+			t.Errorf("%s GetByRank(7) = %+v, %v; want player3", name, got, ok)
+		}
+
+		lb.Reset()
+		if lb.Len() != 0 || len(lb.GetTopN(10)) != 0 {
+			t.Errorf("%s Reset() left %d players", name, lb.Len())
+		}
+		if _, ok := lb.GetPlayerRank("player5"); ok {
+			t.Errorf("%s GetPlayerRank(player5) after Reset() should not exist", name)
+		}
+		lb.UpdateScore("player1", 10, base)
+		if got, ok := lb.GetPlayerRank("player1"); !ok || got.Rank != 1 {
+			t.Errorf("%s GetPlayerRank(player1) after Reset() = %+v, %v", name, got, ok)
+		}
+	}
+}
+
+func TestLeaderboard_IncrementScore(t *testing.T) {
+	for name, newBoard := range boardConstructors {
+		// 并发加分不应丢失更新
+		lb := newBoard()
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					lb.IncrementScore("player1", 1, time.Now())
+				}
+			}()
+		}
+		wg.Wait()
+		if got, _ := lb.GetPlayerRank("player1"); got.Score != 1000 {
+			t.Errorf("%s concurrent IncrementScore() score = %d; want 1000", name, got.Score)
+		}
+
+		// 默认每次加分都刷新时间戳，加 0 分也会排到同分玩家之后
+		base := time.Now()
+		lb = newBoard()
+		lb.UpdateScore("A", 10, base)
+		lb.UpdateScore("B", 10, base.Add(time.Second))
+		if got := lb.IncrementScore("A", 0, base.Add(2*time.Second)); got != (RankInfo{"A", 10, 2}) {
+			t.Errorf("%s IncrementScore(A, 0) = %+v; want rank 2", name, got)
+		}
+		if got := lb.IncrementScore("C", 5, base); got != (RankInfo{"C", 5, 3}) {
+			t.Errorf("%s IncrementScore(C, 5) = %+v; want new player at rank 3", name, got)
+		}
+
+		// TimestampOnChange 下分数不变时保留原时间戳
+		lb = newBoard(WithTimestampMode(TimestampOnChange))
+		lb.UpdateScore("A", 10, base)
+		lb.UpdateScore("B", 10, base.Add(time.Second))
+		if got := lb.IncrementScore("A", 0, base.Add(2*time.Second)); got != (RankInfo{"A", 10, 1}) {
+			t.Errorf("%s TimestampOnChange IncrementScore(A, 0) = %+v; want rank 1", name, got)
+		}
+		lb.UpdateScore("A", 10, base.Add(3*time.Second))
+		if got, _ := lb.GetPlayerRank("A"); got.Rank != 1 {
+			t.Errorf("%s TimestampOnChange UpdateScore(A, 10) rank = %d; want 1", name, got.Rank)
+		}
+		if got := lb.IncrementScore("B", -1, base.Add(4*time.Second)); got != (RankInfo{"B", 9, 2}) {
+			t.Errorf("%s TimestampOnChange IncrementScore(B, -1) = %+v", name, got)
+		}
+	}
+}
+
+func TestLeaderboard_UpdatePolicy(t *testing.T) {
+	tests := []struct {
+		policy UpdatePolicy
+		scores []int
+		want   int
+	}{
+		{PolicyReplace, []int{50, 80, 30}, 30},
+		{PolicyKeepBest, []int{50, 80, 30}, 80},
+		{PolicyKeepWorst, []int{50, 80, 30}, 30},
+		{PolicyAccumulate, []int{50, 80, 30}, 160},
+	}
+	for name, newBoard := range boardConstructors {
+		for _, tt := range tests {
+			lb := newBoard(WithUpdatePolicy(tt.policy))
+			for _, score := range tt.scores {
+				lb.UpdateScore("A", score, time.Now())
+			}
+			if got, _ := lb.GetPlayerRank("A"); got.Score != tt.want {
+				t.Errorf("%s policy %d score = %d; want %d", name, tt.policy, got.Score, tt.want)
+			}
+		}
+
+		// 更新结果反映分数和名次的变化
+		base := time.Now()
+		lb := newBoard(WithUpdatePolicy(PolicyKeepBest))
+		if res := lb.UpdateScore("A", 50, base); res.Existed || !res.ScoreChanged || !res.RankChanged || res.New != (RankInfo{"A", 50, 1}) {
+			t.Errorf("%s UpdateScore(A, 50) = %+v", name, res)
+		}
+		lb.UpdateScore("B", 60, base)
+		res := lb.UpdateScore("A", 40, base.Add(time.Second))
+		if !res.Existed || res.ScoreChanged || res.RankChanged || res.Old != (RankInfo{"A", 50, 2}) || res.New != res.Old {
+			t.Errorf("%s UpdateScore(A, 40) = %+v; want unchanged", name, res)
+		}
+		res = lb.UpdateScore("A", 70, base.Add(time.Second))
+		if !res.ScoreChanged || !res.RankChanged || res.Old.Rank != 2 || res.New != (RankInfo{"A", 70, 1}) {
+			t.Errorf("%s UpdateScore(A, 70) = %+v", name, res)
+		}
+	}
+}
+
+func TestLeaderboard_AscendingOrder(t *testing.T) {
+	for name, newBoard := range boardConstructors {
+		base := time.Now()
+		lb := newBoard(WithOrder(Ascending), WithUpdatePolicy(PolicyKeepBest))
+		lb.UpdateScore("A", 95, base)
+		lb.UpdateScore("B", 90, base.Add(time.Second))
+		lb.UpdateScore("C", 90, base)
+		lb.UpdateScore("D", 120, base)
+		lb.UpdateScore("D", 130, base) // 更慢的成绩不会覆盖最好成绩
+
+		want := []RankInfo{{"C", 90, 1}, {"B", 90, 2}, {"A", 95, 3}, {"D", 120, 4}}
+		if got := lb.GetTopN(4); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s ascending GetTopN(4) = %+v; want %+v", name, got, want)
+		}
+		if got, _ := lb.GetPlayerRank("A"); got.Rank != 3 {
+			t.Errorf("%s ascending GetPlayerRank(A) = %+v; want rank 3", name, got)
+		}
+		if got := lb.GetPlayerRankRange("B", 1); fmt.Sprint(got) != fmt.Sprint(want[:3]) {
+			t.Errorf("%s ascending GetPlayerRankRange(B, 1) = %+v; want %+v", name, got, want[:3])
+		}
+		if got := lb.GetRankRange(4, 4); len(got) != 1 || got[0] != want[3] {
+			t.Errorf("%s ascending GetRankRange(4, 4) = %+v; want %+v", name, got, want[3:])
+		}
+	}
+
+	// 密集排名同样遵循升序，且负分不会与初始值混淆
+	lb := NewLeaderboardLinkedList(WithOrder(Ascending))
+	lb.UpdateScore("A", -1, time.Now())
+	lb.UpdateScore("B", 3, time.Now())
+	lb.UpdateScore("C", 3, time.Now())
+	if got := lb.GetDensePlayerRank("A"); got.Rank != 1 {
+		t.Errorf("ascending GetDensePlayerRank(A) = %+v; want rank 1", got)
+	}
+	if got := lb.GetDensePlayerRank("C"); got.Rank != 2 {
+		t.Errorf("ascending GetDensePlayerRank(C) = %+v; want rank 2", got)
+	}
+}
+
+func TestLeaderboard_RankingMode(t *testing.T) {
+	tests := []struct {
+		mode  RankingMode
+		want  []int // A B C D E 的名次
+		wantE int   // E 追平 D 之后的名次
+	}{
+		{OrdinalRanking, []int{1, 2, 3, 4, 5}, 5},
+		{StandardCompetitionRanking, []int{1, 2, 2, 4, 5}, 4},
+		{ModifiedCompetitionRanking, []int{1, 3, 3, 4, 5}, 5},
+		{DenseRanking, []int{1, 2, 2, 3, 4}, 3},
+	}
+	for name, newBoard := range boardConstructors {
+		for _, tt := range tests {
+			base := time.Now()
+			lb := newBoard(WithRankingMode(tt.mode))
+			lb.UpdateScore("A", 100, base)
+			lb.UpdateScore("B", 90, base)
+			lb.UpdateScore("C", 90, base.Add(time.Second))
+			lb.UpdateScore("D", 80, base)
+			lb.UpdateScore("E", 70, base)
+
+			var want []RankInfo
+			for i, id := range []string{"A", "B", "C", "D", "E"} {
+				got, _ := lb.GetPlayerRank(id)
+				want = append(want, RankInfo{id, got.Score, tt.want[i]})
+				if got.Rank != tt.want[i] {
+					t.Errorf("%s %T GetPlayerRank(%s) = %+v; want rank %d", name, tt.mode, id, got, tt.want[i])
+				}
+			}
+			if got := lb.GetTopN(5); fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("%s %T GetTopN(5) = %+v; want %+v", name, tt.mode, got, want)
+			}
+			if got := lb.GetRankRange(2, 4); fmt.Sprint(got) != fmt.Sprint(want[1:4]) {
+				t.Errorf("%s %T GetRankRange(2, 4) = %+v; want %+v", name, tt.mode, got, want[1:4])
+			}
+			if got := lb.GetPlayerRankRange("D", 1); fmt.Sprint(got) != fmt.Sprint(want[2:]) {
+				t.Errorf("%s %T GetPlayerRankRange(D, 1) = %+v; want %+v", name, tt.mode, got, want[2:])
+			}
+			if got, _ := lb.GetByRank(3); got != want[2] {
+				t.Errorf("%s %T GetByRank(3) = %+v; want %+v", name, tt.mode, got, want[2])
+			}
+			if got := lb.IncrementScore("E", 10, base); got.Rank != tt.wantE {
+				t.Errorf("%s %T IncrementScore(E, 10) = %+v; want rank %d", name, tt.mode, got, tt.wantE)
+			}
+		}
+	}
+}
+
+func BenchmarkSkipListGetDensePlayerRank(b *testing.B) {
+	lb := NewLeaderboardSkipList()
+	for i := 0; i < numPlayers; i++ {
+		playerID := fmt.Sprintf("player%d", i)
+		score := rand.Intn(1000)
+		timestamp := time.Now()
+		lb.UpdateScore(playerID, score, timestamp)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		playerID := fmt.Sprintf("player%d", rand.Intn(numPlayers))
+		lb.GetDensePlayerRank(playerID)
+	}
+}
+
+func TestLeaderboardSkipList_DenseRankMatchesLinkedList(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, order := range []SortOrder{Descending, Ascending} {
+		linked := NewLeaderboardLinkedList(WithOrder(order))
+		skip := NewLeaderboardSkipList(WithOrder(order))
+		base := time.Now()
+
+		// 随机更新和删除，分数范围较小以制造大量同分情况
+		for i := 0; i < 5000; i++ {
+			playerID := fmt.Sprintf("player%d", r.Intn(200))
+			if r.Intn(10) == 0 {
+				linked.RemovePlayer(playerID)
+				skip.RemovePlayer(playerID)
+				continue
+			}
+			score := r.Intn(40) - 20
+			timestamp := base.Add(time.Duration(r.Intn(20)) * time.Second)
+			linked.UpdateScore(playerID, score, timestamp)
+			skip.UpdateScore(playerID, score, timestamp)
+		}
+
+		if got, want := skip.GetDenseTopN(50), linked.GetDenseTopN(50); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("order %d GetDenseTopN(50) = %+v; want %+v", order, got, want)
+		}
+		for i := 0; i < 200; i++ {
+			playerID := fmt.Sprintf("player%d", i)
+			if got, want := skip.GetDensePlayerRank(playerID), linked.GetDensePlayerRank(playerID); got != want {
+				t.Fatalf("order %d GetDensePlayerRank(%s) = %+v; want %+v", order, playerID, got, want)
+			}
+			got, want := skip.GetDensePlayerRankRange(playerID, 2), linked.GetDensePlayerRankRange(playerID, 2)
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Fatalf("order %d GetDensePlayerRankRange(%s, 2) = %+v; want %+v", order, playerID, got, want)
+			}
+		}
+	}
+}
+
+func TestLeaderboard_ScoreRange(t *testing.T) {
+	for name, newBoard := range boardConstructors {
+		for _, order := range []SortOrder{Descending, Ascending} {
+			lb := newBoard(WithOrder(order))
+			base := time.Now()
+			for i := 1; i <= 20; i++ {
+				lb.UpdateScore(fmt.Sprintf("player%d", i), i*50, base) // 50, 100, ..., 1000
+			}
+
+			tests := []struct {
+				r    ScoreRange
+				want int
+			}{
+				{ScoreBetween(500, 1000), 11},
+				{ScoreRange{Min: 500, Max: 1000, MinExclusive: true}, 10},
+				{ScoreRange{Min: 500, Max: 1000, MinExclusive: true, MaxExclusive: true}, 9},
+				{ScoreAtLeast(900), 3},
+				{ScoreAtMost(99), 1},
+				{ScoreBetween(501, 549), 0},
+				{ScoreBetween(600, 500), 0},
+			}
+			for _, tt := range tests {
+				if got := lb.CountByScore(tt.r); got != tt.want {
+					t.Errorf("%s order %d CountByScore(%+v) = %d; want %d", name, order, tt.r, got, tt.want)
+				}
+				if got := lb.GetByScore(tt.r, 0, 0); len(got) != tt.want {
+					t.Errorf("%s order %d GetByScore(%+v) length = %d; want %d", name, order, tt.r, len(got), tt.want)
+				}
+			}
+
+			// 分页结果与完整结果的切片一致，且名次与 GetPlayerRank 一致
+			all := lb.GetByScore(ScoreBetween(200, 800), 0, 0)
+			page := lb.GetByScore(ScoreBetween(200, 800), 2, 3)
+			if fmt.Sprint(page) != fmt.Sprint(all[2:5]) {
+				t.Errorf("%s order %d GetByScore(offset 2, limit 3) = %+v; want %+v", name, order, page, all[2:5])
+			}
+			for _, info := range all {
+				if want, _ := lb.GetPlayerRank(info.PlayerID); info != want {
+					t.Errorf("%s order %d GetByScore() entry = %+v; want %+v", name, order, info, want)
+				}
+			}
+			if got := lb.GetByScore(ScoreBetween(200, 800), 20, 5); got != nil {
+				t.Errorf("%s order %d GetByScore(offset 20) = %+v; want nil", name, order, got)
+			}
+		}
+	}
+}
+
+func TestLeaderboard_Percentile(t *testing.T) {
+	for name, newBoard := range boardConstructors {
+		lb := newBoard()
+		if got := lb.GetTopPercent(10); got != nil {
+			t.Errorf("%s empty GetTopPercent(10) = %+v; want nil", name, got)
+		}
+		base := time.Now()
+		for i := 1; i <= 200; i++ {
+			lb.UpdateScore(fmt.Sprintf("player%d", i), i, base)
+		}
+
+		tests := []struct {
+			playerID string
+			want     float64
+		}{
+			{"player200", 99.5},
+			{"player175", 87},
+			{"player1", 0},
+		}
+		for _, tt := range tests {
+			if got, ok := lb.GetPlayerPercentile(tt.playerID); !ok || got != tt.want {
+				t.Errorf("%s GetPlayerPercentile(%s) = %v, %v; want %v", name, tt.playerID, got, ok, tt.want)
+			}
+		}
+		if _, ok := lb.GetPlayerPercentile("unknown"); ok {
+			t.Errorf("%s GetPlayerPercentile(unknown) should not exist", name)
+		}
+
+		if got := lb.GetTopPercent(1); len(got) != 2 || got[1] != (RankInfo{"player199", 199, 2}) {
+			t.Errorf("%s GetTopPercent(1) = %+v; want top 2", name, got)
+		}
+		if got := lb.GetTopPercent(0.1); len(got) != 1 {
+			t.Errorf("%s GetTopPercent(0.1) length = %d; want at least 1", name, len(got))
+		}
+		if got := lb.GetTopPercent(150); len(got) != 200 {
+			t.Errorf("%s GetTopPercent(150) length = %d; want 200", name, len(got))
+		}
+
+		// 密集排名按不同分数计算百分位，同分玩家一起入选
+		lb = newBoard(WithRankingMode(DenseRanking))
+		for i, score := range []int{100, 100, 90, 80, 80, 80, 70, 60, 50, 40} {
+			lb.UpdateScore(fmt.Sprintf("player%d", i), score, base)
+		}
+		if got, _ := lb.GetPlayerPercentile("player1"); got != 85.71 {
+			t.Errorf("%s dense GetPlayerPercentile(player1) = %v; want 85.71", name, got)
+		}
+		if got, _ := lb.GetPlayerPercentile("player4"); got != 57.14 {
+			t.Errorf("%s dense GetPlayerPercentile(player4) = %v; want 57.14", name, got)
+		}
+		got := lb.GetTopPercent(40) // 7 个不同分数，上限为第 3 名
+		if len(got) != 6 || got[5] != (RankInfo{"player5", 80, 3}) {
+			t.Errorf("%s dense GetTopPercent(40) = %+v", name, got)
+		}
+	}
+}
+
+func TestLeaderboard_DensePlayerRankRangeBounds(t *testing.T) {
+	base := time.Now()
+	for name, newBoard := range boardConstructors {
+		lb := newBoard(WithRankingMode(DenseRanking))
+		for i, score := range []int{100, 100, 90, 80} {
+			lb.UpdateScore(fmt.Sprintf("player%d", i), score, base)
+		}
+		ranker := lb.(DenseRanker)
+		// rangeN 为负数时没有符合条件的名次，末名玩家的起始名次超出不同分数的个数
+		for _, playerID := range []string{"player0", "player3"} {
+			if got := ranker.GetDensePlayerRankRange(playerID, -1); len(got) != 0 {
+				t.Errorf("%s GetDensePlayerRankRange(%s, -1) = %+v; want empty", name, playerID, got)
+			}
+		}
+		if got := ranker.GetDensePlayerRankRange("player2", 100); len(got) != 4 || got[3] != (RankInfo{"player3", 80, 3}) {
+			t.Errorf("%s GetDensePlayerRankRange(player2, 100) = %+v; want all 4 players", name, got)
+		}
+	}
+}
+
+func TestLeaderboard_Load(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	base := time.Now()
+	src := NewLeaderboardSkipList()
+	for i := 0; i < 2000; i++ {
+		src.UpdateScore(fmt.Sprintf("player%d", i), r.Intn(100), base.Add(time.Duration(r.Intn(10))*time.Second))
+	}
+	var players []Player
+	src.ForEach(func(p Player) bool {
+		players = append(players, p)
+		return true
+	})
+
+	for name, newBoard := range boardConstructors {
+		lb := newBoard(WithRankingMode(DenseRanking))
+		lb.UpdateScore("stale", 1, base)
+		if err := lb.(BulkLoader).Load(players); err != nil {
+			t.Fatalf("%s Load() error = %v", name, err)
+		}
+		if _, ok := lb.GetPlayerRank("stale"); ok || lb.Len() != len(players) {
+			t.Fatalf("%s Load() should replace the board, Len() = %d", name, lb.Len())
+		}
+
+		// 装载后的排行榜与逐个插入的排行榜结果一致，并可以继续更新
+		want := NewLeaderboardLinkedList(WithRankingMode(DenseRanking))
+		for _, p := range players {
+			want.UpdateScore(p.PlayerID, p.Score, p.Timestamp)
+		}
+		for i := 0; i < 200; i++ {
+			playerID := fmt.Sprintf("player%d", r.Intn(2000))
+			score := r.Intn(100)
+			lb.UpdateScore(playerID, score, base)
+			want.UpdateScore(playerID, score, base)
+		}
+		for _, rank := range []int{1, 2, 500, 1999, 2000} {
+			got, _ := lb.GetByRank(rank)
+			exp, _ := want.GetByRank(rank)
+			if got != exp {
+				t.Errorf("%s GetByRank(%d) = %+v; want %+v", name, rank, got, exp)
+			}
+		}
+		if got, exp := lb.GetPlayerRankRange("player7", 3), want.GetPlayerRankRange("player7", 3); fmt.Sprint(got) != fmt.Sprint(exp) {
+			t.Errorf("%s GetPlayerRankRange(player7, 3) = %+v; want %+v", name, got, exp)
+		}
+
+		unsorted := []Player{{"a", 1, base}, {"b", 2, base}}
+		if err := lb.(BulkLoader).Load(unsorted); !errors.Is(err, ErrUnsorted) {
+			t.Errorf("%s Load(unsorted) error = %v; want ErrUnsorted", name, err)
+		}
+		if err := lb.(BulkLoader).Load([]Player{{"a", 2, base}, {"a", 1, base}}); err == nil {
+			t.Errorf("%s Load(duplicate) should fail", name)
+		}
+		if lb.Len() != len(players) {
+			t.Errorf("%s failed Load() changed the board, Len() = %d", name, lb.Len())
+		}
+	}
+}
+
+func TestLeaderboard_Events(t *testing.T) {
+	for name, newBoard := range boardConstructors {
+		bus := NewEventBus(EventBusConfig{TopN: 2, MaxOvertaken: 2})
+		lb := newBoard(WithEventBus(bus))
+		base := time.Now()
+		for i, id := range []string{"A", "B", "C", "D"} {
+			lb.UpdateScore(id, 100-i*10, base) // 没有监听器时不产生事件
+		}
+
+		events := make(chan string, 100)
+		cancel := bus.Subscribe(func(e Event) {
+			s := fmt.Sprintf("%v %s %d->%d", e.Type, e.PlayerID, e.Old.Rank, e.New.Rank)
+			if e.By != "" {
+				s += " by " + e.By
+			}
+			events <- s
+		})
+		// 慢监听器不阻塞写入
+		release := make(chan struct{})
+		cancelSlow := bus.Subscribe(func(Event) { <-release })
+
+		lb.UpdateScore("D", 95, base)
+		lb.RemovePlayer("A")
+		lb.UpdateScore("E", 10, base)
+		lb.UpdateScore("E", 10, base) // 分数和时间戳都没有变化，不产生事件
+		close(release)
+		cancelSlow()
+
+		want := []string{
+			"RankChanged D 4->2", "ScoreChanged D 4->2", "EnteredTopN D 4->2",
+			"Overtaken B 2->3 by D", "LeftTopN B 2->3", "Overtaken C 3->4 by D",
+			"RankChanged A 1->0", "LeftTopN A 1->0", "EnteredTopN B 3->2",
+			"RankChanged E 0->4",
+		}
+		for i, w := range want {
+			select {
+			case got := <-events:
+				if got != w {
+					t.Errorf("%s event %d = %q; want %q", name, i, got, w)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s timed out waiting for event %q", name, w)
+			}
+		}
+
+		cancel()
+		lb.UpdateScore("E", 200, base)
+		select {
+		case got := <-events:
+			t.Errorf("%s got event %q after cancel", name, got)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+func TestEventBus_DropsWhenQueueFull(t *testing.T) {
+	bus := NewEventBus(EventBusConfig{QueueSize: 3})
+	lb := NewLeaderboardSkipList(WithEventBus(bus))
+
+	var received int64
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	cancel := bus.Subscribe(func(Event) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		atomic.AddInt64(&received, 1)
+	})
+	defer cancel()
+
+	// 第一个事件被回调协程取走并阻塞，之后队列最多积压 3 个事件
+	lb.UpdateScore("p0", 0, time.Now())
+	<-started
+	for i := 1; i <= 10; i++ {
+		lb.UpdateScore(fmt.Sprintf("p%d", i), i, time.Now())
+	}
+	if got := bus.Dropped(); got != 7 {
+		t.Errorf("Dropped() = %d; want 7", got)
+	}
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt64(&received) < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := atomic.LoadInt64(&received); got != 4 {
+		t.Errorf("listener received %d events; want 4", got)
+	}
+}
+
+func TestLeaderboard_MaxPlayers(t *testing.T) {
+	base := time.Now()
+	for name, newBoard := range boardConstructors {
+		lb := newBoard(WithMaxPlayers(2))
+		lb.UpdateScore("A", 10, base)
+		lb.UpdateScore("B", 20, base)
+
+		if res := lb.UpdateScore("C", 30, base); !res.Rejected || lb.Len() != 2 {
+			t.Errorf("%s UpdateScore(C) on full board = %+v, Len() = %d; want rejected", name, res, lb.Len())
+		}
+		if info := lb.IncrementScore("C", 30, base); info != (RankInfo{}) {
+			t.Errorf("%s IncrementScore(C) on full board = %+v; want empty", name, info)
+		}
+		// 已上榜玩家的更新不受上限影响，删除玩家后新玩家可以上榜
+		if res := lb.UpdateScore("A", 40, base); res.Rejected || res.New.Rank != 1 {
+			t.Errorf("%s UpdateScore(A) = %+v; want rank 1", name, res)
+		}
+		lb.RemovePlayer("B")
+		if info := lb.IncrementScore("C", 30, base); info.Rank != 2 {
+			t.Errorf("%s IncrementScore(C) after removal = %+v; want rank 2", name, info)
+		}
+
+		players := []Player{{"x", 3, base}, {"y", 2, base}, {"z", 1, base}}
+		if err := lb.(BulkLoader).Load(players); !errors.Is(err, ErrBoardFull) {
+			t.Errorf("%s Load(3 players) error = %v; want ErrBoardFull", name, err)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(RegistryConfig{MaxBoards: 3, MaxPlayersPerBoard: 100})
+	base := time.Now()
+
+	race, err := r.Create("race:eu", BoardConfig{Implementation: ImplLinkedList, Order: Ascending})
+	if err != nil {
+		t.Fatal(err)
+	}
+	race.UpdateScore("A", 90, base)
+	race.UpdateScore("B", 80, base)
+	if info, _ := race.GetPlayerRank("B"); info.Rank != 1 {
+		t.Errorf("ascending board GetPlayerRank(B) = %+v; want rank 1", info)
+	}
+	if _, err := r.Create("arena", BoardConfig{RankingMode: DenseRanking, UpdatePolicy: PolicyKeepBest, MaxPlayers: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Create("race:eu", BoardConfig{}); !errors.Is(err, ErrBoardExists) {
+		t.Errorf("Create(duplicate) error = %v; want ErrBoardExists", err)
+	}
+	if _, err := r.Create("big", BoardConfig{MaxPlayers: 101}); err == nil {
+		t.Error("Create() with MaxPlayers over the registry limit should fail")
+	}
+	if _, err := r.Create("", BoardConfig{}); err == nil {
+		t.Error("Create() with empty name should fail")
+	}
+	if _, err := r.Create("race:us", BoardConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Create("race:asia", BoardConfig{}); !errors.Is(err, ErrTooManyBoards) {
+		t.Errorf("Create() over MaxBoards error = %v; want ErrTooManyBoards", err)
+	}
+
+	arena, ok := r.Get("arena")
+	if !ok {
+		t.Fatal("Get(arena) not found")
+	}
+	arena.UpdateScore("A", 10, base)
+	arena.UpdateScore("A", 5, base)
+	if res := arena.UpdateScore("B", 10, base); !res.Rejected {
+		t.Errorf("arena UpdateScore(B) = %+v; want rejected by per-board limit", res)
+	}
+
+	infos := r.List()
+	var names []string
+	for _, info := range infos {
+		names = append(names, fmt.Sprintf("%s:%d:%d", info.Name, info.Players, info.Config.MaxPlayers))
+	}
+	if got, want := fmt.Sprint(names), "[arena:1:1 race:eu:2:100 race:us:0:100]"; got != want {
+		t.Errorf("List() = %s; want %s", got, want)
+	}
+	if infos[0].Config.RankingMode != DenseRanking || infos[0].Config.UpdatePolicy != PolicyKeepBest {
+		t.Errorf("List() arena config = %+v", infos[0].Config)
+	}
+
+	if !r.Delete("race:eu") || r.Delete("race:eu") {
+		t.Error("Delete(race:eu) should succeed exactly once")
+	}
+	if _, ok := r.Get("race:eu"); ok || r.Len() != 2 {
+		t.Errorf("board still registered after Delete(), Len() = %d", r.Len())
+	}
+	if _, err := r.Create("race:asia", BoardConfig{}); err != nil {
+		t.Errorf("Create() after Delete() error = %v", err)
+	}
+
+	// 并发创建、查找、删除
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("tmp%d", i%2)
+			for j := 0; j < 100; j++ {
+				if board, err := r.Create(name, BoardConfig{}); err == nil {
+					board.UpdateScore("A", j, base)
+				}
+				if board, ok := r.Get(name); ok {
+					board.GetTopN(1)
+				}
+				r.List()
+				r.Delete(name)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if r.Len() > 3 {
+		t.Errorf("registry exceeded MaxBoards, Len() = %d", r.Len())
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	from := time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC) // 周三
+	tests := []struct {
+		spec string
+		loc  *time.Location
+		want time.Time
+	}{
+		{"@daily", nil, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1", nil, time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", nil, time.Date(2024, 2, 1, 10, 30, 0, 0, time.UTC)}, // 严格晚于 from
+		{"*/15 * * * *", nil, time.Date(2024, 1, 31, 10, 45, 0, 0, time.UTC)},
+		{"0 4 1,15 * *", nil, time.Date(2024, 2, 1, 4, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", nil, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", nil, time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)}, // 日期和星期满足其一
+		{"0 0 * * 7", nil, time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"@daily", shanghai, time.Date(2024, 2, 1, 0, 0, 0, 0, shanghai)},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.spec, tt.loc)
+		if err != nil {
+			t.Errorf("ParseCron(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next() = %v; want %v", tt.spec, got, tt.want)
+		}
+	}
+	if got := Weekly(time.Monday, nil).Next(from); !got.Equal(time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Weekly(Monday).Next() = %v", got)
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "0 0 30 2 *", "a * * * *"} {
+		if _, err := ParseCron(spec, nil); err == nil {
+			t.Errorf("ParseCron(%q) should fail", spec)
+		}
+	}
+}
+
+func TestKeepPercent(t *testing.T) {
+	tests := []struct {
+		percent, score, want int
+	}{
+		{50, 81, 40},
+		{33, -150, -49},
+		{50, math.MaxInt, math.MaxInt / 2},
+		{10, math.MaxInt, math.MaxInt / 10},
+		{100, math.MinInt, math.MinInt},
+	}
+	for _, tt := range tests {
+		if got, _ := KeepPercent(tt.percent)(tt.score); got != tt.want {
+			t.Errorf("KeepPercent(%d)(%d) = %d; want %d", tt.percent, tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestSeasonalBoard(t *testing.T) {
+	for name, newBoard := range boardConstructors {
+		now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		sb := NewSeasonalBoard(SeasonConfig{
+			Schedule:    Daily(nil),
+			Now:         func() time.Time { return now },
+			Decay:       KeepPercent(50),
+			MaxArchives: 2,
+			NewBoard:    newBoard,
+		}, WithRankingMode(StandardCompetitionRanking))
+
+		sb.UpdateScore("A", 100, now)
+		sb.UpdateScore("B", 81, now.Add(time.Second))
+		sb.UpdateScore("C", 1, now)
+		if s := sb.Season(); s.Number != 1 || !s.End.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("%s Season() = %+v", name, s)
+		}
+
+		// 到达切换时间后的第一次操作切换赛季，新赛季按规则结转：A 50，B 40，C 不结转
+		now = time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+		if got := fmt.Sprint(sb.GetTopN(5)); got != "[{A 50 1} {B 40 2}]" {
+			t.Errorf("%s carried top = %s", name, got)
+		}
+		if s := sb.Season(); s.Number != 2 || !s.Start.Equal(now) {
+			t.Errorf("%s Season() after rollover = %+v", name, s)
+		}
+		archive, ok := sb.Archive(1)
+		if !ok || archive.Len() != 3 || !archive.End.Equal(now) {
+			t.Fatalf("%s Archive(1) = %+v, %v", name, archive, ok)
+		}
+		if info, _ := archive.GetByRank(2); info.PlayerID != "B" || info.Score != 81 {
+			t.Errorf("%s archive GetByRank(2) = %+v", name, info)
+		}
+
+		// D 与 A 结转后同分，保留原时间戳，D 仍排在 A 之前
+		sb.UpdateScore("D", 81, now)
+		sb.UpdateScore("A", 80, now.Add(time.Second))
+		// 归档冻结，新赛季的写入不影响归档
+		if info, _ := archive.GetPlayerRank("A"); info.Score != 100 {
+			t.Errorf("%s archive changed after rollover: %+v", name, info)
+		}
+
+		// 跳过两天，错过的赛季合并为一个：只归档一次、只结转一次，新赛季到下一个切换时间结束
+		now = time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+		if s := sb.Season(); s.Number != 3 || !s.Start.Equal(time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)) ||
+			!s.End.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s Season() after gap = %+v", name, s)
+		}
+		if got := fmt.Sprint(sb.GetTopN(5)); got != "[{D 40 1} {A 40 1} {B 20 3}]" {
+			t.Errorf("%s top after gap = %s", name, got)
+		}
+		if a, ok := sb.Archive(2); !ok || fmt.Sprint(a.GetTopN(1)) != "[{D 81 1}]" || !a.End.Equal(time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s Archive(2) = %+v, top %v", name, a, a.GetTopN(1))
+		}
+
+		// 手动结束赛季，下一赛季的结束时间从现在算起，只保留最近 2 个归档
+		sb.Rollover()
+		if s := sb.Season(); s.Number != 4 || !s.Start.Equal(now) || !s.End.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s Season() after Rollover() = %+v", name, s)
+		}
+		if _, ok := sb.Archive(1); ok || len(sb.Archives()) != 2 {
+			t.Errorf("%s archive 1 should be dropped, archives = %d", name, len(sb.Archives()))
+		}
+	}
+
+	// 未设置 MaxArchives 时最多保留 DefaultMaxArchives 个归档
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	sb := NewSeasonalBoard(SeasonConfig{Schedule: Daily(nil), Now: func() time.Time { return now }})
+	for i := 0; i < DefaultMaxArchives+5; i++ {
+		sb.Rollover()
+	}
+	if got := len(sb.Archives()); got != DefaultMaxArchives {
+		t.Errorf("default archives = %d; want %d", got, DefaultMaxArchives)
+	}
+}
+
+func TestShardedBoard_MatchesSkipList(t *testing.T) {
+	modes := map[string]RankingMode{
+		"Ordinal":  OrdinalRanking,
+		"Standard": StandardCompetitionRanking,
+		"Modified": ModifiedCompetitionRanking,
+		"Dense":    DenseRanking,
+	}
+	for modeName, mode := range modes {
+		for _, order := range []SortOrder{Descending, Ascending} {
+			r := rand.New(rand.NewSource(5))
+			opts := []Option{WithRankingMode(mode), WithOrder(order)}
+			want := NewLeaderboardSkipList(opts...)
+			got := NewShardedBoard(7, opts...)
+			base := time.Now()
+			name := fmt.Sprintf("%s/%d", modeName, order)
+
+			// 分数和时间戳范围都很小，制造大量同分、同时间戳的情况
+			for i := 0; i < 3000; i++ {
+				playerID := fmt.Sprintf("player%d", r.Intn(400))
+				score := r.Intn(40)
+				timestamp := base.Add(time.Duration(r.Intn(5)) * time.Second)
+				switch r.Intn(10) {
+				case 0:
+					if w, g := want.RemovePlayer(playerID), got.RemovePlayer(playerID); w != g {
+						t.Fatalf("%s RemovePlayer(%s) = %v; want %v", name, playerID, g, w)
+					}
+				case 1:
+					if w, g := want.IncrementScore(playerID, score-20, timestamp), got.IncrementScore(playerID, score-20, timestamp); w != g {
+						t.Fatalf("%s IncrementScore(%s) = %+v; want %+v", name, playerID, g, w)
+					}
+				default:
+					if w, g := want.UpdateScore(playerID, score, timestamp), got.UpdateScore(playerID, score, timestamp); w != g {
+						t.Fatalf("%s UpdateScore(%s) = %+v; want %+v", name, playerID, g, w)
+					}
+				}
+			}
+
+			check := func(what string, g, w interface{}) {
+				t.Helper()
+				if fmt.Sprint(g) != fmt.Sprint(w) {
+					t.Fatalf("%s %s = %v; want %v", name, what, g, w)
+				}
+			}
+			check("Len()", got.Len(), want.Len())
+			check("GetTopN(50)", got.GetTopN(50), want.GetTopN(50))
+			check("GetRankRange(100, 180)", got.GetRankRange(100, 180), want.GetRankRange(100, 180))
+			check("GetByScore", got.GetByScore(ScoreBetween(10, 20), 5, 30), want.GetByScore(ScoreBetween(10, 20), 5, 30))
+			check("CountByScore", got.CountByScore(ScoreAtLeast(15)), want.CountByScore(ScoreAtLeast(15)))
+			check("GetTopPercent(10)", got.GetTopPercent(10), want.GetTopPercent(10))
+			check("GetDenseTopN(20)", got.GetDenseTopN(20), want.GetDenseTopN(20))
+			for rank := 0; rank <= want.Len()+1; rank += 13 {
+				g, gOk := got.GetByRank(rank)
+				w, wOk := want.GetByRank(rank)
+				check(fmt.Sprintf("GetByRank(%d)", rank), fmt.Sprint(g, gOk), fmt.Sprint(w, wOk))
+			}
+			for i := 0; i < 400; i += 7 {
+				playerID := fmt.Sprintf("player%d", i)
+				g, gOk := got.GetPlayerRank(playerID)
+				w, wOk := want.GetPlayerRank(playerID)
+				check("GetPlayerRank("+playerID+")", fmt.Sprint(g, gOk), fmt.Sprint(w, wOk))
+				for _, rangeN := range []int{0, 1, 4} {
+					check(fmt.Sprintf("GetPlayerRankRange(%s, %d)", playerID, rangeN),
+						got.GetPlayerRankRange(playerID, rangeN), want.GetPlayerRankRange(playerID, rangeN))
+				}
+				check("GetDensePlayerRankRange("+playerID+")", got.GetDensePlayerRankRange(playerID, 2), want.GetDensePlayerRankRange(playerID, 2))
+				gp, _ := got.GetPlayerPercentile(playerID)
+				wp, _ := want.GetPlayerPercentile(playerID)
+				check("GetPlayerPercentile("+playerID+")", gp, wp)
+			}
+
+			// 批量装载后与原排行榜一致
+			var players []Player
+			want.ForEach(func(p Player) bool {
+				players = append(players, p)
+				return true
+			})
+			loaded := NewShardedBoard(3, opts...)
+			if err := loaded.Load(players); err != nil {
+				t.Fatalf("%s Load() error = %v", name, err)
+			}
+			check("loaded GetRankRange(1, 500)", loaded.GetRankRange(1, 500), want.GetRankRange(1, 500))
+		}
+	}
+}
+
+func TestShardedBoard_Concurrent(t *testing.T) {
+	lb := NewShardedBoard(8, WithMaxPlayers(300))
+	base := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			for i := 0; i < 2000; i++ {
+				playerID := fmt.Sprintf("player%d", r.Intn(500))
+				switch r.Intn(6) {
+				case 0:
+					lb.RemovePlayer(playerID)
+				case 1:
+					lb.GetPlayerRankRange(playerID, 3)
+				case 2:
+					lb.GetTopN(10)
+				default:
+					lb.UpdateScore(playerID, r.Intn(100), base.Add(time.Duration(i)))
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// 并发写入结束后，各项查询与按同样内容装载的单个排行榜一致
+	var players []Player
+	lb.ForEach(func(p Player) bool {
+		players = append(players, p)
+		return true
+	})
+	if len(players) != lb.Len() || lb.Len() > 300 {
+		t.Fatalf("ForEach() visited %d players, Len() = %d", len(players), lb.Len())
+	}
+	want := NewLeaderboardSkipList()
+	if err := want.Load(players); err != nil {
+		t.Fatalf("ForEach() is not in rank order: %v", err)
+	}
+	for _, p := range players {
+		got, _ := lb.GetPlayerRank(p.PlayerID)
+		if exp, _ := want.GetPlayerRank(p.PlayerID); got != exp {
+			t.Fatalf("GetPlayerRank(%s) = %+v; want %+v", p.PlayerID, got, exp)
+		}
+	}
+}
+
+func TestShardedBoard_ConcurrentUpdateResults(t *testing.T) {
+	lb := NewShardedBoard(4, WithUpdatePolicy(PolicyAccumulate), WithRankingMode(DenseRanking))
+	base := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				if w%2 == 1 {
+					lb.UpdateScore(fmt.Sprintf("player%d", (w*500+i)%50), 1, base) // 其他分片上的并发写入
+					continue
+				}
+				// 同一玩家的并发累加：每次更新的结果都恰好反映本次加 1
+				res := lb.UpdateScore("hot", 1, base)
+				if res.Existed && (res.New.Score != res.Old.Score+1 || !res.ScoreChanged) {
+					t.Errorf("UpdateScore(hot) = %+v; want score increased by exactly 1", res)
+					return
+				}
+				if res.RankChanged != (res.Old.Rank != res.New.Rank) {
+					t.Errorf("UpdateScore(hot) = %+v; RankChanged disagrees with ranks", res)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if info, _ := lb.GetPlayerRank("hot"); info.Score != 2000 {
+		t.Errorf("GetPlayerRank(hot) = %+v; want score 2000", info)
+	}
+}
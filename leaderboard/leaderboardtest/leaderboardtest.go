@@ -0,0 +1,289 @@
+// Package leaderboardtest 提供 LeaderboardService 实现的一致性测试
+//
+// 测试按随机种子生成包含写操作和各类查询的操作流，同时在被测排行榜和参考模型上执行，逐个比较每个操作的结果。
+// 参考模型按文档描述的语义直接实现，覆盖排序方向、排名策略、更新策略、时间戳刷新方式和玩家数上限的全部组合。
+// 发现不一致时，测试会删减操作流，得到仍然出错的最短操作序列，并给出复现所需的随机种子。
+//
+// 新的实现只需调用 Register 注册构造函数，即可通过 Run 纳入测试；也可以用 Test 单独测试一个构造函数
+package leaderboardtest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"game/leaderboard"
+)
+
+// Constructor 按配置项创建一个空排行榜
+type Constructor func(opts ...leaderboard.Option) leaderboard.LeaderboardService
+
+var (
+	registryMu   sync.Mutex
+	constructors = make(map[string]Constructor)
+)
+
+func init() {
+	Register("skiplist", func(opts ...leaderboard.Option) leaderboard.LeaderboardService {
+		return leaderboard.NewLeaderboardSkipList(opts...)
+	})
+	Register("linkedlist", func(opts ...leaderboard.Option) leaderboard.LeaderboardService {
+		return leaderboard.NewLeaderboardLinkedList(opts...)
+	})
+	Register("sharded", func(opts ...leaderboard.Option) leaderboard.LeaderboardService {
+		return leaderboard.NewShardedBoard(4, opts...)
+	})
+}
+
+// Register 以 name 注册实现的构造函数，名称重复或构造函数为 nil 时 panic
+func Register(name string, newBoard Constructor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if newBoard == nil {
+		panic("leaderboardtest: Register constructor is nil")
+	}
+	if _, dup := constructors[name]; dup {
+		panic("leaderboardtest: Register called twice for " + name)
+	}
+	constructors[name] = newBoard
+}
+
+// Implementations 返回已注册的实现名称，按名称排序
+func Implementations() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	names := make([]string, 0, len(constructors))
+	for name := range constructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup 按名称查找已注册的构造函数
+func Lookup(name string) (Constructor, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	newBoard, ok := constructors[name]
+	return newBoard, ok
+}
+
+// Profile 一组排行榜配置，参考模型按它模拟排行榜的行为
+type Profile struct {
+	Order         leaderboard.SortOrder
+	RankingMode   leaderboard.RankingMode // 为 nil 时使用序数排名
+	UpdatePolicy  leaderboard.UpdatePolicy
+	TimestampMode leaderboard.TimestampMode
+	MaxPlayers    int
+}
+
+func (p Profile) rankingMode() leaderboard.RankingMode {
+	if p.RankingMode == nil {
+		return leaderboard.OrdinalRanking
+	}
+	return p.RankingMode
+}
+
+// Options 返回创建排行榜所用的配置项
+func (p Profile) Options() []leaderboard.Option {
+	return []leaderboard.Option{
+		leaderboard.WithOrder(p.Order),
+		leaderboard.WithRankingMode(p.rankingMode()),
+		leaderboard.WithUpdatePolicy(p.UpdatePolicy),
+		leaderboard.WithTimestampMode(p.TimestampMode),
+		leaderboard.WithMaxPlayers(p.MaxPlayers),
+	}
+}
+
+func (p Profile) String() string {
+	order := "desc"
+	if p.Order == leaderboard.Ascending {
+		order = "asc"
+	}
+	ranking := map[leaderboard.RankingMode]string{
+		leaderboard.OrdinalRanking:             "ordinal",
+		leaderboard.StandardCompetitionRanking: "standard",
+		leaderboard.ModifiedCompetitionRanking: "modified",
+		leaderboard.DenseRanking:               "dense",
+	}[p.rankingMode()]
+	policy := [...]string{"replace", "best", "worst", "accumulate"}[p.UpdatePolicy]
+	ts := [...]string{"always", "onchange"}[p.TimestampMode]
+	return fmt.Sprintf("%s/%s/%s/ts-%s/max%d", order, ranking, policy, ts, p.MaxPlayers)
+}
+
+// Profiles 返回排序方向、排名策略、更新策略、时间戳刷新方式和玩家数上限（不限制或 8 人）的全部组合
+func Profiles() []Profile {
+	var profiles []Profile
+	for _, order := range []leaderboard.SortOrder{leaderboard.Descending, leaderboard.Ascending} {
+		for _, ranking := range []leaderboard.RankingMode{
+			leaderboard.OrdinalRanking, leaderboard.StandardCompetitionRanking,
+			leaderboard.ModifiedCompetitionRanking, leaderboard.DenseRanking,
+		} {
+			for _, policy := range []leaderboard.UpdatePolicy{
+				leaderboard.PolicyReplace, leaderboard.PolicyKeepBest,
+				leaderboard.PolicyKeepWorst, leaderboard.PolicyAccumulate,
+			} {
+				for _, ts := range []leaderboard.TimestampMode{leaderboard.TimestampAlways, leaderboard.TimestampOnChange} {
+					for _, maxPlayers := range []int{0, 8} {
+						profiles = append(profiles, Profile{order, ranking, policy, ts, maxPlayers})
+					}
+				}
+			}
+		}
+	}
+	return profiles
+}
+
+// Config 一致性测试的配置，零值使用默认值
+type Config struct {
+	Seed     int64     // 第一条操作流的随机种子，之后的操作流依次加一，默认 1
+	Streams  int       // 每组配置运行的操作流数，默认 2
+	Ops      int       // 每条操作流的长度，默认 300
+	Players  int       // 操作流涉及的玩家数，默认 12
+	Profiles []Profile // 测试的配置组合，默认 Profiles()
+}
+
+func (c Config) withDefaults() Config {
+	if c.Seed == 0 {
+		c.Seed = 1
+	}
+	if c.Streams <= 0 {
+		c.Streams = 2
+	}
+	if c.Ops <= 0 {
+		c.Ops = 300
+	}
+	if c.Players <= 0 {
+		c.Players = 12
+	}
+	if c.Profiles == nil {
+		c.Profiles = Profiles()
+	}
+	return c
+}
+
+// Failure 被测排行榜与参考模型的结果不一致
+type Failure struct {
+	Profile Profile
+	Seed    int64       // 生成原始操作流的随机种子
+	Ops     []Op        // 最小化后的操作序列，最后一个操作的结果不一致
+	Got     interface{} // 被测排行榜的结果
+	Want    interface{} // 参考模型的结果
+}
+
+func (f *Failure) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "profile %v, seed %d: %d ops reproduce the mismatch\n", f.Profile, f.Seed, len(f.Ops))
+	for i, op := range f.Ops {
+		fmt.Fprintf(&b, "  %d: %v\n", i+1, op)
+	}
+	fmt.Fprintf(&b, "got  %+v\nwant %+v", f.Got, f.Want)
+	return b.String()
+}
+
+// Check 在新建的排行榜和参考模型上依次执行 ops，返回第一个结果不一致的操作序号（从 0 开始）及两者的结果
+// 全部一致时序号为 -1；排行榜没有实现的可选接口的操作不比较，操作 panic 视为结果不一致
+func Check(newBoard Constructor, p Profile, ops []Op) (int, interface{}, interface{}) {
+	board := newBoard(p.Options()...)
+	m := newModel(p)
+	for i, op := range ops {
+		got, want := safeApply(board, op), m.apply(op)
+		if _, skip := got.(unsupported); !skip && !equal(got, want) {
+			return i, got, want
+		}
+	}
+	return -1, nil, nil
+}
+
+// Panic 被测排行榜执行操作时 panic，作为该操作的结果参与比较
+type Panic struct {
+	Value interface{}
+}
+
+func (p Panic) String() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// safeApply 执行操作，panic 时返回 Panic，使失败的操作流同样可以最小化
+func safeApply(board leaderboard.LeaderboardService, op Op) (res interface{}) {
+	defer func() {
+		if v := recover(); v != nil {
+			res = Panic{v}
+		}
+	}()
+	return apply(board, op)
+}
+
+// Verify 用随机种子 seed 生成一条操作流进行检查，不一致时返回最小化后的失败用例
+func Verify(newBoard Constructor, p Profile, seed int64, n, players int) *Failure {
+	ops := Generate(seed, n, players)
+	i, _, _ := Check(newBoard, p, ops)
+	if i < 0 {
+		return nil
+	}
+	ops = Minimize(newBoard, p, ops[:i+1])
+	_, got, want := Check(newBoard, p, ops)
+	return &Failure{Profile: p, Seed: seed, Ops: ops, Got: got, Want: want}
+}
+
+// Minimize 删减仍会出错的操作序列，返回删去任意一段都不再出错的较短序列，最后一个操作的结果不一致
+// 按 delta debugging 的思路先尝试删除大段操作，再逐步缩小删除的粒度
+func Minimize(newBoard Constructor, p Profile, ops []Op) []Op {
+	fails := func(candidate []Op) ([]Op, bool) {
+		i, _, _ := Check(newBoard, p, candidate)
+		if i < 0 {
+			return nil, false
+		}
+		return candidate[:i+1], true
+	}
+	ops, ok := fails(ops)
+	if !ok {
+		return ops
+	}
+	for chunk := len(ops) / 2; chunk >= 1; {
+		removed := false
+		for start := 0; start < len(ops); {
+			candidate := append(append([]Op(nil), ops[:start]...), ops[min(start+chunk, len(ops)):]...)
+			if shorter, ok := fails(candidate); ok {
+				ops, removed = shorter, true
+				continue // 同一位置继续尝试删除
+			}
+			start += chunk
+		}
+		if !removed {
+			chunk /= 2
+		}
+	}
+	return ops
+}
+
+// equal 比较两个操作结果，空切片与 nil 视为相等
+func equal(a, b interface{}) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []leaderboard.RankInfo:
+		if len(v) == 0 {
+			return nil
+		}
+	case []leaderboard.Player:
+		if len(v) == 0 {
+			return nil
+		}
+	}
+	return v
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
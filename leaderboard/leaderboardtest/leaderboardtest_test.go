@@ -0,0 +1,71 @@
+package leaderboardtest
+
+import (
+	"strings"
+	"testing"
+
+	"game/leaderboard"
+)
+
+func TestConformance(t *testing.T) {
+	Run(t, Config{Streams: 4})
+}
+
+// offByOneBoard 只有在玩家数达到 5 人后 Len 才出错的实现，用于验证能否发现并最小化错误
+type offByOneBoard struct {
+	leaderboard.LeaderboardService
+}
+
+func (b offByOneBoard) Len() int {
+	if n := b.LeaderboardService.Len(); n < 5 {
+		return n
+	}
+	return b.LeaderboardService.Len() + 1
+}
+
+func TestVerify_MinimizesFailure(t *testing.T) {
+	broken := func(opts ...leaderboard.Option) leaderboard.LeaderboardService {
+		return offByOneBoard{leaderboard.NewLeaderboardSkipList(opts...)}
+	}
+	var f *Failure
+	for seed := int64(1); f == nil && seed < 10; seed++ {
+		f = Verify(broken, Profile{}, seed, 300, 12)
+	}
+	if f == nil {
+		t.Fatal("Verify() did not detect the broken Len")
+	}
+	// 最短的复现序列为 5 次新玩家写入加一次 Len
+	if len(f.Ops) != 6 || f.Ops[5].Kind != OpLen || f.Got != 6 || f.Want != 5 {
+		t.Errorf("minimized failure:\n%v\nwant 5 writes followed by Len()", f)
+	}
+	if !strings.Contains(f.Error(), "6: Len()") {
+		t.Errorf("Error() = %q; want the failing op listed", f.Error())
+	}
+}
+
+func TestRegister(t *testing.T) {
+	names := strings.Join(Implementations(), ",")
+	if names != "linkedlist,sharded,skiplist" {
+		t.Errorf("Implementations() = %s; want the built-in implementations", names)
+	}
+	Register("test-only", func(opts ...leaderboard.Option) leaderboard.LeaderboardService {
+		return leaderboard.NewLeaderboardSkipList(opts...)
+	})
+	defer func() {
+		registryMu.Lock()
+		delete(constructors, "test-only")
+		registryMu.Unlock()
+	}()
+	newBoard, ok := Lookup("test-only")
+	if !ok {
+		t.Fatal("Lookup(test-only) not found")
+	}
+	Test(t, newBoard, Config{Streams: 1, Ops: 50, Profiles: []Profile{{MaxPlayers: 3}}})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() with duplicate name did not panic")
+		}
+	}()
+	Register("test-only", newBoard)
+}
@@ -0,0 +1,313 @@
+package leaderboardtest
+
+import (
+	"math"
+	"sort"
+
+	"game/leaderboard"
+)
+
+// model 参考模型，按 LeaderboardService 文档描述的语义实现排行榜
+// 只用映射保存玩家，每次查询都完整排序后按定义计算，追求直观正确而不追求性能
+type model struct {
+	p       Profile
+	players map[string]leaderboard.Player
+}
+
+func newModel(p Profile) *model {
+	return &model{p: p, players: make(map[string]leaderboard.Player)}
+}
+
+// better 判断分数 a 是否严格优于分数 b
+func (m *model) better(a, b int) bool {
+	if m.p.Order == leaderboard.Ascending {
+		return a < b
+	}
+	return a > b
+}
+
+// sorted 按分数、时间戳、玩家ID排序的全部玩家
+func (m *model) sorted() []leaderboard.Player {
+	players := make([]leaderboard.Player, 0, len(m.players))
+	for _, p := range m.players {
+		players = append(players, p)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+		if a.Score != b.Score {
+			return m.better(a.Score, b.Score)
+		}
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		return a.PlayerID < b.PlayerID
+	})
+	return players
+}
+
+// rank 按排名策略计算 players[i] 的名次
+func (m *model) rank(players []leaderboard.Player, i int) int {
+	score := players[i].Score
+	switch m.p.rankingMode() {
+	case leaderboard.StandardCompetitionRanking:
+		rank := 1
+		for _, p := range players {
+			if m.better(p.Score, score) {
+				rank++
+			}
+		}
+		return rank
+	case leaderboard.ModifiedCompetitionRanking:
+		rank := 0
+		for _, p := range players {
+			if !m.better(score, p.Score) {
+				rank++
+			}
+		}
+		return rank
+	case leaderboard.DenseRanking:
+		distinct := make(map[int]bool)
+		for _, p := range players {
+			if m.better(p.Score, score) {
+				distinct[p.Score] = true
+			}
+		}
+		return len(distinct) + 1
+	}
+	return i + 1
+}
+
+// infos 返回序数名次区间 [first, last] 内玩家的排名信息，区间会被截断到 [1, 玩家总数]
+func (m *model) infos(players []leaderboard.Player, first, last int) []leaderboard.RankInfo {
+	var res []leaderboard.RankInfo
+	for i := first - 1; i < last && i < len(players); i++ {
+		if i >= 0 {
+			res = append(res, leaderboard.RankInfo{PlayerID: players[i].PlayerID, Score: players[i].Score, Rank: m.rank(players, i)})
+		}
+	}
+	return res
+}
+
+// find 返回玩家的序数名次，不存在时返回 0
+func (m *model) find(players []leaderboard.Player, playerID string) int {
+	for i, p := range players {
+		if p.PlayerID == playerID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (m *model) info(playerID string) (leaderboard.RankInfo, bool) {
+	players := m.sorted()
+	if ordinal := m.find(players, playerID); ordinal > 0 {
+		return m.infos(players, ordinal, ordinal)[0], true
+	}
+	return leaderboard.RankInfo{}, false
+}
+
+// write 写入分数，时间戳按刷新方式计算
+func (m *model) write(playerID string, score int, op Op) {
+	ts := op.timestamp()
+	if old, ok := m.players[playerID]; ok && m.p.TimestampMode == leaderboard.TimestampOnChange && old.Score == score {
+		ts = old.Timestamp
+	}
+	m.players[playerID] = leaderboard.Player{PlayerID: playerID, Score: score, Timestamp: ts}
+}
+
+func (m *model) full(playerID string) bool {
+	_, exists := m.players[playerID]
+	return !exists && m.p.MaxPlayers > 0 && len(m.players) >= m.p.MaxPlayers
+}
+
+func (m *model) updateScore(op Op) leaderboard.UpdateResult {
+	if m.full(op.PlayerID) {
+		return leaderboard.UpdateResult{Rejected: true}
+	}
+	old, existed := m.info(op.PlayerID)
+	score, write := op.Score, true
+	if existed {
+		switch m.p.UpdatePolicy {
+		case leaderboard.PolicyKeepBest:
+			write = m.better(score, old.Score)
+		case leaderboard.PolicyKeepWorst:
+			write = m.better(old.Score, score)
+		case leaderboard.PolicyAccumulate:
+			score += old.Score
+		}
+	}
+	if write {
+		m.write(op.PlayerID, score, op)
+	}
+	updated, _ := m.info(op.PlayerID)
+	return leaderboard.UpdateResult{
+		Old:          old,
+		New:          updated,
+		Existed:      existed,
+		ScoreChanged: !existed || old.Score != updated.Score,
+		RankChanged:  !existed || old.Rank != updated.Rank,
+	}
+}
+
+func (m *model) incrementScore(op Op) leaderboard.RankInfo {
+	if m.full(op.PlayerID) {
+		return leaderboard.RankInfo{}
+	}
+	m.write(op.PlayerID, m.players[op.PlayerID].Score+op.Score, op)
+	info, _ := m.info(op.PlayerID)
+	return info
+}
+
+// percentileBase 计算百分位所用的总体规模：密集排名下为不同分数的个数，其他排名策略下为玩家总数
+func (m *model) percentileBase() int {
+	if m.p.rankingMode() != leaderboard.DenseRanking {
+		return len(m.players)
+	}
+	distinct := make(map[int]bool)
+	for _, p := range m.players {
+		distinct[p.Score] = true
+	}
+	return len(distinct)
+}
+
+func (m *model) percentile(playerID string) (float64, bool) {
+	info, ok := m.info(playerID)
+	if !ok {
+		return 0, false
+	}
+	base := m.percentileBase()
+	v := float64(base-info.Rank) / float64(base) * 100
+	return math.Floor(v*100+1e-9) / 100, true
+}
+
+func (m *model) topPercent(p float64) []leaderboard.RankInfo {
+	base := m.percentileBase()
+	if p <= 0 || base == 0 {
+		return nil
+	}
+	cutoff := int(math.Ceil(float64(base)*math.Min(p, 100)/100 - 1e-9))
+	if cutoff < 1 {
+		cutoff = 1
+	}
+	var res []leaderboard.RankInfo
+	for _, info := range m.infos(m.sorted(), 1, len(m.players)) {
+		if info.Rank <= cutoff {
+			res = append(res, info)
+		}
+	}
+	return res
+}
+
+// denseRange 返回密集排名在玩家密集排名前后各 rangeN 名以内的玩家，名次按密集排名计算，与排名策略无关
+func (m *model) denseRange(playerID string, rangeN int) []leaderboard.RankInfo {
+	players := m.sorted()
+	ranks := make([]int, len(players))
+	for i := range players {
+		ranks[i] = 1
+		if i > 0 {
+			ranks[i] = ranks[i-1]
+			if players[i].Score != players[i-1].Score {
+				ranks[i]++
+			}
+		}
+	}
+	ordinal := m.find(players, playerID)
+	if ordinal == 0 {
+		return nil
+	}
+	cur := ranks[ordinal-1]
+	var res []leaderboard.RankInfo
+	for i, p := range players {
+		if ranks[i] >= cur-rangeN && ranks[i] <= cur+rangeN {
+			res = append(res, leaderboard.RankInfo{PlayerID: p.PlayerID, Score: p.Score, Rank: ranks[i]})
+		}
+	}
+	return res
+}
+
+// apply 在模型上执行操作，结果的形式与 apply 对排行榜执行操作的结果一致
+func (m *model) apply(op Op) interface{} {
+	switch op.Kind {
+	case OpUpdateScore:
+		return m.updateScore(op)
+	case OpIncrementScore:
+		return m.incrementScore(op)
+	case OpRemovePlayers:
+		removed := 0
+		for _, id := range op.PlayerIDs {
+			if _, ok := m.players[id]; ok {
+				delete(m.players, id)
+				removed++
+			}
+		}
+		return removed
+	case OpReset:
+		m.players = make(map[string]leaderboard.Player)
+		return nil
+	case OpGetPlayerRank:
+		info, ok := m.info(op.PlayerID)
+		return []interface{}{info, ok}
+	case OpGetTopN:
+		return m.infos(m.sorted(), 1, op.N)
+	case OpGetPlayerRankRange:
+		players := m.sorted()
+		ordinal := m.find(players, op.PlayerID)
+		if ordinal == 0 {
+			return nil
+		}
+		return m.infos(players, ordinal-op.N, ordinal+op.N)
+	case OpGetByRank:
+		players := m.sorted()
+		if op.N < 1 || op.N > len(players) {
+			return []interface{}{leaderboard.RankInfo{}, false}
+		}
+		return []interface{}{m.infos(players, op.N, op.N)[0], true}
+	case OpGetRankRange:
+		return m.infos(m.sorted(), op.N, op.M)
+	case OpLen:
+		return len(m.players)
+	case OpCountByScore:
+		count := 0
+		for _, p := range m.players {
+			if op.Range.Contains(p.Score) {
+				count++
+			}
+		}
+		return count
+	case OpGetByScore:
+		var res []leaderboard.RankInfo
+		skipped := 0
+		players := m.sorted()
+		for i, p := range players {
+			if !op.Range.Contains(p.Score) {
+				continue
+			}
+			if skipped < op.N {
+				skipped++
+				continue
+			}
+			if op.M > 0 && len(res) == op.M {
+				break
+			}
+			res = append(res, m.infos(players, i+1, i+1)...)
+		}
+		return res
+	case OpGetPlayerPercentile:
+		p, ok := m.percentile(op.PlayerID)
+		return []interface{}{p, ok}
+	case OpGetTopPercent:
+		return m.topPercent(op.Percent)
+	case OpForEach:
+		var players []leaderboard.Player
+		for _, p := range m.sorted() {
+			players = append(players, p)
+			if len(players) == op.N {
+				break
+			}
+		}
+		return players
+	case OpGetDensePlayerRankRange:
+		return m.denseRange(op.PlayerID, op.N)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package leaderboardtest
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"game/leaderboard"
+)
+
+// OpKind 操作类型，与 LeaderboardService 和 leaderboard.DenseRanker 的方法一一对应
+type OpKind int
+
+const (
+	OpUpdateScore OpKind = iota
+	OpIncrementScore
+	OpRemovePlayers
+	OpReset
+	OpGetPlayerRank
+	OpGetTopN
+	OpGetPlayerRankRange
+	OpGetByRank
+	OpGetRankRange
+	OpLen
+	OpCountByScore
+	OpGetByScore
+	OpGetPlayerPercentile
+	OpGetTopPercent
+	OpForEach
+	OpGetDensePlayerRankRange // 只在实现了 leaderboard.DenseRanker 的排行榜上检查
+)
+
+// baseTime 操作流中时间戳的基准，时间戳只取整秒偏移，便于产生同分同时间戳的玩家
+var baseTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Op 操作流中的一个操作，各字段按 Kind 取用
+type Op struct {
+	Kind      OpKind
+	PlayerID  string                 // 单个玩家的操作
+	PlayerIDs []string               // OpRemovePlayers
+	Score     int                    // OpUpdateScore 的分数或 OpIncrementScore 的增量
+	Seconds   int                    // 写操作的时间戳，相对 baseTime 的秒数
+	N, M      int                    // 数量、名次或区间：GetTopN(N)、GetRankRange(N, M)、GetByScore 的 offset N 和 limit M、ForEach 遍历 N 名后停止、周边排名的 rangeN
+	Range     leaderboard.ScoreRange // OpCountByScore、OpGetByScore
+	Percent   float64                // OpGetTopPercent
+}
+
+func (op Op) timestamp() time.Time {
+	return baseTime.Add(time.Duration(op.Seconds) * time.Second)
+}
+
+// String 以方法调用的形式输出操作，便于直接写成复现用例
+func (op Op) String() string {
+	switch op.Kind {
+	case OpUpdateScore:
+		return fmt.Sprintf("UpdateScore(%q, %d, t+%ds)", op.PlayerID, op.Score, op.Seconds)
+	case OpIncrementScore:
+		return fmt.Sprintf("IncrementScore(%q, %d, t+%ds)", op.PlayerID, op.Score, op.Seconds)
+	case OpRemovePlayers:
+		return fmt.Sprintf("RemovePlayers(%q)", op.PlayerIDs)
+	case OpReset:
+		return "Reset()"
+	case OpGetPlayerRank:
+		return fmt.Sprintf("GetPlayerRank(%q)", op.PlayerID)
+	case OpGetTopN:
+		return fmt.Sprintf("GetTopN(%d)", op.N)
+	case OpGetPlayerRankRange:
+		return fmt.Sprintf("GetPlayerRankRange(%q, %d)", op.PlayerID, op.N)
+	case OpGetByRank:
+		return fmt.Sprintf("GetByRank(%d)", op.N)
+	case OpGetRankRange:
+		return fmt.Sprintf("GetRankRange(%d, %d)", op.N, op.M)
+	case OpLen:
+		return "Len()"
+	case OpCountByScore:
+		return fmt.Sprintf("CountByScore(%+v)", op.Range)
+	case OpGetByScore:
+		return fmt.Sprintf("GetByScore(%+v, %d, %d)", op.Range, op.N, op.M)
+	case OpGetPlayerPercentile:
+		return fmt.Sprintf("GetPlayerPercentile(%q)", op.PlayerID)
+	case OpGetTopPercent:
+		return fmt.Sprintf("GetTopPercent(%v)", op.Percent)
+	case OpForEach:
+		return fmt.Sprintf("ForEach(stop after %d)", op.N)
+	case OpGetDensePlayerRankRange:
+		return fmt.Sprintf("GetDensePlayerRankRange(%q, %d)", op.PlayerID, op.N)
+	}
+	return fmt.Sprintf("Op(%d)", int(op.Kind))
+}
+
+// Generate 按随机种子生成长度为 n 的操作流，玩家ID取自 players 个玩家，另有少量不存在的玩家
+// 分数和时间戳的取值范围很小，保证经常出现同分、同时间戳的玩家
+func Generate(seed int64, n, players int) []Op {
+	rng := rand.New(rand.NewSource(seed))
+	player := func() string {
+		if rng.Intn(20) == 0 {
+			return "missing"
+		}
+		return fmt.Sprintf("p%d", rng.Intn(players))
+	}
+	span := func() int { return rng.Intn(players+4) - 2 }
+	percents := []float64{-1, 0, 0.5, 10, 33.3, 50, 99.9, 100, 150}
+
+	ops := make([]Op, n)
+	for i := range ops {
+		op := &ops[i]
+		switch r := rng.Intn(100); {
+		case r < 30:
+			op.Kind, op.PlayerID, op.Score, op.Seconds = OpUpdateScore, player(), rng.Intn(21)-5, rng.Intn(10)
+		case r < 42:
+			op.Kind, op.PlayerID, op.Score, op.Seconds = OpIncrementScore, player(), rng.Intn(11)-5, rng.Intn(10)
+		case r < 48:
+			op.Kind = OpRemovePlayers
+			for j := rng.Intn(3) + 1; j > 0; j-- {
+				op.PlayerIDs = append(op.PlayerIDs, player())
+			}
+		case r < 49:
+			op.Kind = OpReset
+		default:
+			op.Kind = OpKind(int(OpGetPlayerRank) + rng.Intn(int(OpGetDensePlayerRankRange-OpGetPlayerRank)+1))
+			op.PlayerID, op.N, op.M = player(), span(), span()
+			lo := rng.Intn(26) - 8
+			op.Range = leaderboard.ScoreRange{
+				Min: lo, Max: lo + rng.Intn(15) - 2,
+				MinExclusive: rng.Intn(3) == 0, MaxExclusive: rng.Intn(3) == 0,
+			}
+			op.Percent = percents[rng.Intn(len(percents))]
+		}
+	}
+	return ops
+}
+
+// unsupported 排行榜没有实现操作对应的可选接口时 apply 的结果，Check 不比较此类结果
+type unsupported struct{}
+
+// apply 对排行榜执行操作并返回结果，结果的类型与方法的返回值对应，多个返回值放在切片中
+func apply(board leaderboard.LeaderboardService, op Op) interface{} {
+	switch op.Kind {
+	case OpUpdateScore:
+		return board.UpdateScore(op.PlayerID, op.Score, op.timestamp())
+	case OpIncrementScore:
+		return board.IncrementScore(op.PlayerID, op.Score, op.timestamp())
+	case OpRemovePlayers:
+		return board.RemovePlayers(op.PlayerIDs...)
+	case OpReset:
+		board.Reset()
+		return nil
+	case OpGetPlayerRank:
+		info, ok := board.GetPlayerRank(op.PlayerID)
+		return []interface{}{info, ok}
+	case OpGetTopN:
+		return board.GetTopN(op.N)
+	case OpGetPlayerRankRange:
+		return board.GetPlayerRankRange(op.PlayerID, op.N)
+	case OpGetByRank:
+		info, ok := board.GetByRank(op.N)
+		return []interface{}{info, ok}
+	case OpGetRankRange:
+		return board.GetRankRange(op.N, op.M)
+	case OpLen:
+		return board.Len()
+	case OpCountByScore:
+		return board.CountByScore(op.Range)
+	case OpGetByScore:
+		return board.GetByScore(op.Range, op.N, op.M)
+	case OpGetPlayerPercentile:
+		p, ok := board.GetPlayerPercentile(op.PlayerID)
+		return []interface{}{p, ok}
+	case OpGetTopPercent:
+		return board.GetTopPercent(op.Percent)
+	case OpForEach:
+		var players []leaderboard.Player
+		board.ForEach(func(p leaderboard.Player) bool {
+			players = append(players, p)
+			return len(players) != op.N
+		})
+		return players
+	case OpGetDensePlayerRankRange:
+		ranker, ok := board.(leaderboard.DenseRanker)
+		if !ok {
+			return unsupported{}
+		}
+		return ranker.GetDensePlayerRankRange(op.PlayerID, op.N)
+	}
+	return nil
+}
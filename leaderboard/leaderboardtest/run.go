@@ -0,0 +1,28 @@
+package leaderboardtest
+
+import "testing"
+
+// Run 对所有已注册的实现运行一致性测试，每个实现一个子测试
+func Run(t *testing.T, cfg Config) {
+	for _, name := range Implementations() {
+		newBoard, _ := Lookup(name)
+		t.Run(name, func(t *testing.T) {
+			Test(t, newBoard, cfg)
+		})
+	}
+}
+
+// Test 对一个实现运行一致性测试，每组配置一个子测试，失败时报告最小化后的操作序列
+func Test(t *testing.T, newBoard Constructor, cfg Config) {
+	cfg = cfg.withDefaults()
+	for _, p := range cfg.Profiles {
+		p := p
+		t.Run(p.String(), func(t *testing.T) {
+			for i := 0; i < cfg.Streams; i++ {
+				if f := Verify(newBoard, p, cfg.Seed+int64(i), cfg.Ops, cfg.Players); f != nil {
+					t.Fatal(f)
+				}
+			}
+		})
+	}
+}
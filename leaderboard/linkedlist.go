@@ -9,49 +9,185 @@ import (
 // LeaderboardLinkedList 排行榜（链表实现）
 type LeaderboardLinkedList struct {
 	mu        sync.RWMutex
-	players   *list.List               // 按Score降序、Timestamp升序排列的双向链表
+	players   *list.List               // 按排序方向、Timestamp升序排列的双向链表
 	playerMap map[string]*list.Element // 玩家ID到链表节点的映射
+	opts      options                  // 排行榜配置
 }
 
-func NewLeaderboardLinkedList() *LeaderboardLinkedList {
+func NewLeaderboardLinkedList(opts ...Option) *LeaderboardLinkedList {
 	return &LeaderboardLinkedList{
 		players:   list.New(),
 		playerMap: make(map[string]*list.Element),
+		opts:      newOptions(opts),
 	}
 }
 
 // UpdateScore 更新分数（链表插入）
-// 如果玩家已经存在于排行榜中，先删除旧记录，然后插入新记录，保持链表的有序性
-func (l *LeaderboardLinkedList) UpdateScore(playerID string, score int, timestamp time.Time) {
+// 按更新策略计算新分数，如果玩家已经存在于排行榜中，先删除旧记录，然后插入新记录，保持链表的有序性
+func (l *LeaderboardLinkedList) UpdateScore(playerID string, score int, timestamp time.Time) UpdateResult {
 	// 加锁，防止并发需改
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	// 删除旧记录
+	var old RankInfo
+	var oldPlayer *Player
+	elem, existed := l.playerMap[playerID]
+	if existed {
+		oldPlayer = elem.Value.(*Player)
+		old = l.rankInfo(elem)
+	}
+	if !existed && l.opts.exceeds(len(l.playerMap)+1) {
+		return UpdateResult{Rejected: true}
+	}
+
+	if newScore, write := l.opts.policyScore(oldPlayer, score); write {
+		next := &Player{playerID, newScore, l.opts.writeTimestamp(oldPlayer, newScore, timestamp)}
+		rec := l.opts.events.begin(linkedListEvents{l}, playerID, next)
+		elem = l.setScore(playerID, newScore, timestamp)
+		rec.finish(linkedListEvents{l})
+	}
+	return newUpdateResult(old, existed, l.rankInfo(elem))
+}
+
+// IncrementScore 原子地增加玩家分数并返回新的排名信息
+// 玩家不存在时以 0 分为基础；读取旧分数和写入新分数在同一把写锁内完成
+func (l *LeaderboardLinkedList) IncrementScore(playerID string, delta int, timestamp time.Time) RankInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	score := delta
+	var old *Player
+	elem, exists := l.playerMap[playerID]
+	if exists {
+		old = elem.Value.(*Player)
+		score += old.Score
+	}
+	if !exists && l.opts.exceeds(len(l.playerMap)+1) {
+		return RankInfo{}
+	}
+	rec := l.opts.events.begin(linkedListEvents{l}, playerID, &Player{playerID, score, l.opts.writeTimestamp(old, score, timestamp)})
+	elem = l.setScore(playerID, score, timestamp)
+	rec.finish(linkedListEvents{l})
+	return l.rankInfo(elem)
+}
+
+// 写入玩家分数（内部使用），调用方需持有写锁
+// 先删除旧记录，再按排序规则插入新记录，返回玩家所在的链表节点
+func (l *LeaderboardLinkedList) setScore(playerID string, score int, timestamp time.Time) *list.Element {
+	var old *Player
 	if elem, exists := l.playerMap[playerID]; exists {
-		l.players.Remove(elem)
-		delete(l.playerMap, playerID)
+		old = elem.Value.(*Player)
 	}
+	timestamp = l.opts.writeTimestamp(old, score, timestamp)
+	if old != nil && old.Score == score && old.Timestamp.Equal(timestamp) {
+		return l.playerMap[playerID] // 分数和时间戳都没有变化，位置不变
+	}
+
+	// 删除旧记录
+	l.removePlayer(playerID)
 
 	newPlayer := &Player{
 		PlayerID:  playerID,
 		Score:     score,
 		Timestamp: timestamp,
 	}
-	// 遍历链表,链表按分数降序、时间戳升序排列
+	// 遍历链表,链表按分数（排序方向）、时间戳升序排列
 	for e := l.players.Front(); e != nil; e = e.Next() {
-		p := e.Value.(*Player)
-		if p.Score > score || (p.Score == score && p.Timestamp.Before(timestamp)) {
+		if l.opts.before(e.Value.(*Player), newPlayer) {
 			continue
 		}
-		l.players.InsertBefore(newPlayer, e)
-		l.playerMap[playerID] = e.Prev()
-		return
+		l.playerMap[playerID] = l.players.InsertBefore(newPlayer, e)
+		return l.playerMap[playerID]
 	}
 
 	// 遍历完链表都没有找到合适的插入位置，将新节点插入到链表尾部
-	l.players.PushBack(newPlayer)
-	l.playerMap[playerID] = l.players.Back()
+	l.playerMap[playerID] = l.players.PushBack(newPlayer)
+	return l.playerMap[playerID]
+}
+
+// 计算链表节点的序数排名（内部使用），从头遍历计数
+func (l *LeaderboardLinkedList) rankOf(elem *list.Element) int {
+	rank := 1
+	for e := l.players.Front(); e != elem; e = e.Next() {
+		rank++
+	}
+	return rank
+}
+
+// 生成链表节点按排名策略计算的排名信息（内部使用）
+func (l *LeaderboardLinkedList) rankInfo(elem *list.Element) RankInfo {
+	p := elem.Value.(*Player)
+	rank := l.opts.rankingMode.Rank(linkedListCounter{l}, l.rankOf(elem), p.Score)
+	return RankInfo{p.PlayerID, p.Score, rank}
+}
+
+// linkedListCounter 链表实现的分数统计，从链表头部遍历，调用方需持有锁
+type linkedListCounter struct {
+	l *LeaderboardLinkedList
+}
+
+func (c linkedListCounter) CountBetter(score int) int {
+	count := 0
+	for e := c.l.players.Front(); e != nil && c.l.opts.scoreBetter(e.Value.(*Player).Score, score); e = e.Next() {
+		count++
+	}
+	return count
+}
+
+func (c linkedListCounter) CountNotWorse(score int) int {
+	count := 0
+	for e := c.l.players.Front(); e != nil && !c.l.opts.scoreBetter(score, e.Value.(*Player).Score); e = e.Next() {
+		count++
+	}
+	return count
+}
+
+func (c linkedListCounter) CountDistinctBetter(score int) int {
+	count := 0
+	for e := c.l.players.Front(); e != nil && c.l.opts.scoreBetter(e.Value.(*Player).Score, score); e = e.Next() {
+		if prev := e.Prev(); prev == nil || prev.Value.(*Player).Score != e.Value.(*Player).Score {
+			count++
+		}
+	}
+	return count
+}
+
+// linkedListEvents 链表实现的事件查询，从链表头部遍历，调用方需持有锁
+type linkedListEvents struct {
+	l *LeaderboardLinkedList
+}
+
+func (e linkedListEvents) info(playerID string) (RankInfo, int, bool) {
+	elem, exists := e.l.playerMap[playerID]
+	if !exists {
+		return RankInfo{}, 0, false
+	}
+	return e.l.rankInfo(elem), e.l.rankOf(elem), true
+}
+
+func (e linkedListEvents) infoAt(ordinal int) (RankInfo, bool) {
+	if ordinal < 1 || ordinal > e.l.players.Len() {
+		return RankInfo{}, false
+	}
+	elem := e.l.players.Front()
+	for i := 1; i < ordinal; i++ {
+		elem = elem.Next()
+	}
+	return e.l.rankInfo(elem), true
+}
+
+func (e linkedListEvents) countBefore(p *Player) int {
+	count := 0
+	for elem := e.l.players.Front(); elem != nil && e.l.opts.before(elem.Value.(*Player), p); elem = elem.Next() {
+		if elem.Value.(*Player).PlayerID != p.PlayerID {
+			count++
+		}
+	}
+	return count
+}
+
+func (e linkedListEvents) size() int {
+	return e.l.players.Len()
 }
 
 // GetPlayerRank 获取玩家排名 链表遍历计算
@@ -62,18 +198,7 @@ func (l *LeaderboardLinkedList) GetPlayerRank(playerID string) (RankInfo, bool)
 
 	// 检查玩家是否存在于排行榜中
 	if elem, exists := l.playerMap[playerID]; exists {
-		rank := 1
-		// 遍历链表，统计排名
-		for e := l.players.Front(); e != elem; e = e.Next() {
-			rank++
-		}
-		p := elem.Value.(*Player)
-
-		return RankInfo{
-			PlayerID: playerID,
-			Score:    p.Score,
-			Rank:     rank,
-		}, true
+		return l.rankInfo(elem), true // 遍历链表，统计排名
 	}
 
 	// 玩家不存在，返回空的排名信息和 false
@@ -93,7 +218,7 @@ func (l *LeaderboardLinkedList) GetTopN(n int) []RankInfo {
 		res = append(res, RankInfo{p.PlayerID, p.Score, i + 1})
 		i++
 	}
-	return res
+	return applyRanking(l.opts.rankingMode, linkedListCounter{l}, res)
 }
 
 // GetPlayerRankRange 获取周边排名（链表二次遍历）
@@ -103,11 +228,7 @@ func (l *LeaderboardLinkedList) GetPlayerRankRange(playerID string, rangeN int)
 	defer l.mu.RUnlock()
 	// 检查玩家是否存在于排行榜中
 	if elem, exists := l.playerMap[playerID]; exists {
-		currentRank := 1
-		// 遍历链表，统计指定玩家的排名
-		for e := l.players.Front(); e != elem; e = e.Next() {
-			currentRank++
-		}
+		currentRank := l.rankOf(elem) // 遍历链表，统计指定玩家的排名
 
 		start := max(1, currentRank-rangeN)             // 计算排名范围的起始位置
 		end := min(l.players.Len(), currentRank+rangeN) // 计算排名范围的结束位置
@@ -122,8 +243,220 @@ func (l *LeaderboardLinkedList) GetPlayerRankRange(playerID string, rangeN int)
 			}
 			i++
 		}
-		return res
+		return applyRanking(l.opts.rankingMode, linkedListCounter{l}, res)
 	}
 	// 玩家不存在
 	return nil
 }
+
+// GetByRank 获取指定名次的玩家（链表遍历）
+// 名次从1开始，超出范围返回空的排名信息和 false
+func (l *LeaderboardLinkedList) GetByRank(rank int) (RankInfo, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if rank < 1 || rank > l.players.Len() {
+		return RankInfo{}, false
+	}
+	i := 1
+	for e := l.players.Front(); e != nil; e = e.Next() {
+		if i == rank {
+			p := e.Value.(*Player)
+			return RankInfo{p.PlayerID, p.Score, l.opts.rankingMode.Rank(linkedListCounter{l}, rank, p.Score)}, true
+		}
+		i++
+	}
+	return RankInfo{}, false
+}
+
+// GetRankRange 获取名次区间 [start, end] 内的玩家（链表遍历）
+// 区间两端会被截断到 [1, 玩家总数]，区间为空时返回 nil
+func (l *LeaderboardLinkedList) GetRankRange(start, end int) []RankInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	start = max(1, start)
+	end = min(l.players.Len(), end)
+
+	var res []RankInfo
+	i := 0
+	for e := l.players.Front(); e != nil && i < end; e = e.Next() {
+		if i+1 >= start {
+			p := e.Value.(*Player)
+			res = append(res, RankInfo{p.PlayerID, p.Score, i + 1})
+		}
+		i++
+	}
+	return applyRanking(l.opts.rankingMode, linkedListCounter{l}, res)
+}
+
+// Len 获取排行榜玩家总数
+func (l *LeaderboardLinkedList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.players.Len()
+}
+
+// RemovePlayer 删除玩家（链表节点直接摘除）
+// 玩家存在时同时从链表和映射中删除并返回 true，否则返回 false
+func (l *LeaderboardLinkedList) RemovePlayer(playerID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.removeWithEvents(playerID)
+}
+
+// RemovePlayers 批量删除玩家，只加一次锁，返回实际删除的玩家数
+func (l *LeaderboardLinkedList) RemovePlayers(playerIDs ...string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed := 0
+	for _, playerID := range playerIDs {
+		if l.removeWithEvents(playerID) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Reset 清空排行榜
+func (l *LeaderboardLinkedList) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.players.Init()
+	l.playerMap = make(map[string]*list.Element)
+}
+
+// 删除玩家并产生排名变化事件（内部使用），调用方需持有写锁
+func (l *LeaderboardLinkedList) removeWithEvents(playerID string) bool {
+	rec := l.opts.events.begin(linkedListEvents{l}, playerID, nil)
+	removed := l.removePlayer(playerID)
+	rec.finish(linkedListEvents{l})
+	return removed
+}
+
+// 删除玩家（内部使用），调用方需持有写锁
+func (l *LeaderboardLinkedList) removePlayer(playerID string) bool {
+	elem, exists := l.playerMap[playerID]
+	if !exists {
+		return false
+	}
+	l.players.Remove(elem)
+	delete(l.playerMap, playerID)
+	return true
+}
+
+// CountByScore 统计分数区间内的玩家数（链表遍历）
+func (l *LeaderboardLinkedList) CountByScore(r ScoreRange) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	count := 0
+	for e := l.players.Front(); e != nil; e = e.Next() {
+		if r.Contains(e.Value.(*Player).Score) {
+			count++
+		}
+	}
+	return count
+}
+
+// GetByScore 按名次顺序获取分数区间内的玩家（链表遍历）
+// 跳过区间内的前 offset 名玩家，最多返回 limit 名，limit 小于等于 0 表示不限制
+func (l *LeaderboardLinkedList) GetByScore(r ScoreRange, offset, limit int) []RankInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var res []RankInfo
+	skipped := 0
+	i := 0
+	for e := l.players.Front(); e != nil && (limit <= 0 || len(res) < limit); e = e.Next() {
+		i++
+		p := e.Value.(*Player)
+		if !r.Contains(p.Score) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		res = append(res, RankInfo{p.PlayerID, p.Score, i})
+	}
+	return applyRanking(l.opts.rankingMode, linkedListCounter{l}, res)
+}
+
+// GetPlayerPercentile 获取玩家击败的百分比（链表遍历计算排名），计算规则见 percentile
+func (l *LeaderboardLinkedList) GetPlayerPercentile(playerID string) (float64, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if elem, exists := l.playerMap[playerID]; exists {
+		base := percentileBase(l.opts.rankingMode, l.players.Len(), l.countDistinct())
+		return percentile(l.rankInfo(elem).Rank, base), true
+	}
+	return 0, false
+}
+
+// GetTopPercent 获取名次位于前 p% 的玩家（链表遍历），计算规则见 topPercentCutoff
+func (l *LeaderboardLinkedList) GetTopPercent(p float64) []RankInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	cutoff := topPercentCutoff(p, percentileBase(l.opts.rankingMode, l.players.Len(), l.countDistinct()))
+	counter := newCachedCounter(linkedListCounter{l})
+	var res []RankInfo
+	i := 0
+	// 名次随链表顺序单调不减，遇到第一个超过上限的玩家即可停止
+	for e := l.players.Front(); e != nil && cutoff > 0; e = e.Next() {
+		i++
+		p := e.Value.(*Player)
+		rank := l.opts.rankingMode.Rank(counter, i, p.Score)
+		if rank > cutoff {
+			break
+		}
+		res = append(res, RankInfo{p.PlayerID, p.Score, rank})
+	}
+	return res
+}
+
+// 统计不同分数的个数（内部使用），链表中同分玩家相邻
+func (l *LeaderboardLinkedList) countDistinct() int {
+	count := 0
+	for e := l.players.Front(); e != nil; e = e.Next() {
+		if prev := e.Prev(); prev == nil || prev.Value.(*Player).Score != e.Value.(*Player).Score {
+			count++
+		}
+	}
+	return count
+}
+
+// ForEach 按名次顺序遍历玩家（链表遍历），fn 返回 false 时停止
+func (l *LeaderboardLinkedList) ForEach(fn func(p Player) bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for e := l.players.Front(); e != nil; e = e.Next() {
+		if !fn(*e.Value.(*Player)) {
+			return
+		}
+	}
+}
+
+// Load 用已排好序的玩家替换排行榜内容，依次追加到链表尾部，时间复杂度 O(n)
+func (l *LeaderboardLinkedList) Load(players []Player) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.opts.checkSorted(players); err != nil {
+		return err
+	}
+	l.players.Init()
+	l.playerMap = make(map[string]*list.Element, len(players))
+	for i := range players {
+		p := players[i]
+		l.playerMap[p.PlayerID] = l.players.PushBack(&p)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package leaderboard
+
+import "time"
+
+// TimestampMode 表示同分排序所用时间戳的刷新方式
+type TimestampMode int
+
+const (
+	TimestampAlways   TimestampMode = iota // 每次写入分数都刷新时间戳（默认）
+	TimestampOnChange                      // 仅在分数发生变化时刷新时间戳，分数不变时保留最早达到该分数的时间
+)
+
+// UpdatePolicy 表示 UpdateScore 写入已上榜玩家分数时的合并策略
+type UpdatePolicy int
+
+const (
+	PolicyReplace    UpdatePolicy = iota // 以最新分数覆盖旧分数（默认）
+	PolicyKeepBest                       // 只保留最好成绩，新分数更好时才写入
+	PolicyKeepWorst                      // 只保留最差成绩，新分数更差时才写入
+	PolicyAccumulate                     // 新分数累加到旧分数上
+)
+
+// SortOrder 表示排行榜按分数排序的方向
+type SortOrder int
+
+const (
+	Descending SortOrder = iota // 分数从高到低排序（默认）
+	Ascending                   // 分数从低到高排序，适用于竞速、计时类玩法
+)
+
+// options 排行榜配置项
+type options struct {
+	timestampMode TimestampMode // 时间戳刷新方式
+	updatePolicy  UpdatePolicy  // 分数更新策略
+	order         SortOrder     // 排序方向
+	rankingMode   RankingMode   // 排名策略
+	events        *EventBus     // 排名变化事件总线，为 nil 时不产生事件
+	maxPlayers    int           // 玩家数上限，0 表示不限制
+}
+
+// Option 排行榜配置函数，在创建排行榜时传入
+type Option func(*options)
+
+// WithTimestampMode 设置时间戳刷新方式
+func WithTimestampMode(mode TimestampMode) Option {
+	return func(o *options) {
+		o.timestampMode = mode
+	}
+}
+
+// WithUpdatePolicy 设置分数更新策略
+func WithUpdatePolicy(policy UpdatePolicy) Option {
+	return func(o *options) {
+		o.updatePolicy = policy
+	}
+}
+
+// WithOrder 设置排序方向
+func WithOrder(order SortOrder) Option {
+	return func(o *options) {
+		o.order = order
+	}
+}
+
+// WithRankingMode 设置排名策略，决定 RankInfo.Rank 的计算方式
+func WithRankingMode(mode RankingMode) Option {
+	return func(o *options) {
+		o.rankingMode = mode
+	}
+}
+
+// WithEventBus 设置排名变化事件总线，写操作产生的事件通过总线异步投递给监听器
+func WithEventBus(bus *EventBus) Option {
+	return func(o *options) {
+		o.events = bus
+	}
+}
+
+// WithMaxPlayers 设置玩家数上限，排行榜已满时新玩家无法上榜，已上榜玩家的更新不受影响；n 小于等于 0 表示不限制
+func WithMaxPlayers(n int) Option {
+	return func(o *options) {
+		o.maxPlayers = n
+	}
+}
+
+// newOptions 应用配置函数，未设置的配置项使用默认值
+func newOptions(opts []Option) options {
+	o := options{timestampMode: TimestampAlways, updatePolicy: PolicyReplace, order: Descending, rankingMode: OrdinalRanking}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rankingMode == nil {
+		o.rankingMode = OrdinalRanking
+	}
+	return o
+}
+
+// exceeds 判断 n 个玩家是否超过玩家数上限
+func (o *options) exceeds(n int) bool {
+	return o.maxPlayers > 0 && n > o.maxPlayers
+}
+
+// writeTimestamp 计算写入新分数后玩家应使用的时间戳
+// old 为玩家当前记录，玩家不存在时为 nil
+func (o *options) writeTimestamp(old *Player, score int, timestamp time.Time) time.Time {
+	if old != nil && o.timestampMode == TimestampOnChange && old.Score == score {
+		return old.Timestamp
+	}
+	return timestamp
+}
+
+// policyScore 按更新策略计算玩家应写入的分数
+// old 为玩家当前记录，玩家不存在时为 nil；返回 false 表示按策略本次不需要写入
+func (o *options) policyScore(old *Player, score int) (int, bool) {
+	if old == nil {
+		return score, true
+	}
+	switch o.updatePolicy {
+	case PolicyKeepBest:
+		return score, o.scoreBetter(score, old.Score)
+	case PolicyKeepWorst:
+		return score, o.scoreBetter(old.Score, score)
+	case PolicyAccumulate:
+		return old.Score + score, true
+	default:
+		return score, true
+	}
+}
+
+// scoreBetter 判断分数 a 是否严格优于分数 b
+func (o *options) scoreBetter(a, b int) bool {
+	if o.order == Ascending {
+		return a < b
+	}
+	return a > b
+}
+
+// before 判断玩家 a 是否排在玩家 b 之前
+// 分数更优的靠前；分数相同时时间戳早的靠前；分数和时间戳都相同时按玩家ID字典序，保证排序全序唯一
+func (o *options) before(a, b *Player) bool {
+	if a.Score != b.Score {
+		return o.scoreBetter(a.Score, b.Score)
+	}
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+	return a.PlayerID < b.PlayerID
+}
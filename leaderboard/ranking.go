@@ -0,0 +1,137 @@
+package leaderboard
+
+import "math"
+
+// ScoreCounter 为排名策略提供按分数统计的能力
+// 由各排行榜实现在持有锁的情况下提供，score 的优劣按排行榜的排序方向判断
+type ScoreCounter interface {
+	CountBetter(score int) int         // 分数严格优于 score 的玩家数
+	CountNotWorse(score int) int       // 分数不差于 score 的玩家数（包含同分玩家）
+	CountDistinctBetter(score int) int // 严格优于 score 的不同分数个数
+}
+
+// RankingMode 排名策略，根据玩家在排行榜中的序数名次和分数计算 RankInfo.Rank
+// ordinal 为玩家按排序规则（分数、时间戳、玩家ID）得到的唯一名次，从1开始
+type RankingMode interface {
+	Rank(c ScoreCounter, ordinal int, score int) int
+}
+
+var (
+	OrdinalRanking             RankingMode = ordinalRanking{}             // 序数排名 "1234"，同分按时间戳先后排名（默认）
+	StandardCompetitionRanking RankingMode = standardCompetitionRanking{} // 标准竞争排名 "1224"，同分同名次，其后名次跳过
+	ModifiedCompetitionRanking RankingMode = modifiedCompetitionRanking{} // 修正竞争排名 "1334"，同分取最靠后的名次
+	DenseRanking               RankingMode = denseRanking{}               // 密集排名 "1123"，同分同名次，其后名次连续
+)
+
+type ordinalRanking struct{}
+
+func (ordinalRanking) Rank(_ ScoreCounter, ordinal int, _ int) int {
+	return ordinal
+}
+
+type standardCompetitionRanking struct{}
+
+func (standardCompetitionRanking) Rank(c ScoreCounter, _ int, score int) int {
+	return c.CountBetter(score) + 1
+}
+
+type modifiedCompetitionRanking struct{}
+
+func (modifiedCompetitionRanking) Rank(c ScoreCounter, _ int, score int) int {
+	return c.CountNotWorse(score)
+}
+
+type denseRanking struct{}
+
+func (denseRanking) Rank(c ScoreCounter, _ int, score int) int {
+	return c.CountDistinctBetter(score) + 1
+}
+
+// cachedCounter 缓存同一次查询内各分数的统计结果，同分玩家只统计一次
+type cachedCounter struct {
+	c              ScoreCounter
+	better         map[int]int
+	notWorse       map[int]int
+	distinctBetter map[int]int
+}
+
+func newCachedCounter(c ScoreCounter) *cachedCounter {
+	return &cachedCounter{
+		c:              c,
+		better:         make(map[int]int),
+		notWorse:       make(map[int]int),
+		distinctBetter: make(map[int]int),
+	}
+}
+
+func (cc *cachedCounter) CountBetter(score int) int {
+	if n, ok := cc.better[score]; ok {
+		return n
+	}
+	n := cc.c.CountBetter(score)
+	cc.better[score] = n
+	return n
+}
+
+func (cc *cachedCounter) CountNotWorse(score int) int {
+	if n, ok := cc.notWorse[score]; ok {
+		return n
+	}
+	n := cc.c.CountNotWorse(score)
+	cc.notWorse[score] = n
+	return n
+}
+
+func (cc *cachedCounter) CountDistinctBetter(score int) int {
+	if n, ok := cc.distinctBetter[score]; ok {
+		return n
+	}
+	n := cc.c.CountDistinctBetter(score)
+	cc.distinctBetter[score] = n
+	return n
+}
+
+// applyRanking 将按序数名次得到的排名信息批量转换为指定排名策略下的名次
+func applyRanking(mode RankingMode, c ScoreCounter, infos []RankInfo) []RankInfo {
+	if _, ok := mode.(ordinalRanking); ok || len(infos) == 0 {
+		return infos
+	}
+	cc := newCachedCounter(c)
+	for i := range infos {
+		infos[i].Rank = mode.Rank(cc, infos[i].Rank, infos[i].Score)
+	}
+	return infos
+}
+
+// 百分位计算规则：
+//   - 总体规模 base：密集排名下为不同分数的个数，其他排名策略下为玩家总数
+//   - GetPlayerPercentile 返回名次落后于该玩家的比例 (base - rank) / base * 100，保留两位小数并向下取整，
+//     保证不会高估玩家的成绩；排行榜只有一名玩家（或只有一种分数）时为 0
+//   - GetTopPercent(p) 的名次上限为 ceil(base * p / 100)，p 大于 0 时至少为 1，返回名次不超过上限的所有玩家，
+//     因此同名次的玩家会一起入选
+
+// percentileBase 返回排名策略下计算百分位所用的总体规模
+func percentileBase(mode RankingMode, players, distinct int) int {
+	if _, ok := mode.(denseRanking); ok {
+		return distinct
+	}
+	return players
+}
+
+// percentile 计算名次 rank 在总体规模 base 中击败的比例，保留两位小数并向下取整
+func percentile(rank, base int) float64 {
+	if base <= 0 {
+		return 0
+	}
+	v := float64(base-rank) / float64(base) * 100
+	return math.Floor(v*100+1e-9) / 100
+}
+
+// topPercentCutoff 计算前 p% 对应的名次上限，p 小于等于 0 或总体为空时返回 0
+func topPercentCutoff(p float64, base int) int {
+	if p <= 0 || base <= 0 {
+		return 0
+	}
+	p = math.Min(p, 100)
+	return max(1, int(math.Ceil(float64(base)*p/100-1e-9)))
+}
@@ -0,0 +1,173 @@
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Implementation 排行榜的底层实现
+type Implementation int
+
+const (
+	ImplSkipList   Implementation = iota // 跳表实现（默认），各项操作 O(log n)
+	ImplLinkedList                       // 链表实现，写入和按名次查询 O(n)，只适合玩家很少的排行榜
+)
+
+func (impl Implementation) String() string {
+	switch impl {
+	case ImplSkipList:
+		return "skiplist"
+	case ImplLinkedList:
+		return "linkedlist"
+	}
+	return fmt.Sprintf("Implementation(%d)", int(impl))
+}
+
+var (
+	ErrBoardExists   = errors.New("leaderboard: board already exists")
+	ErrTooManyBoards = errors.New("leaderboard: too many boards")
+)
+
+// BoardConfig 注册表中单个排行榜的配置，零值为按分数降序、序数排名、覆盖写入的跳表排行榜
+type BoardConfig struct {
+	Implementation Implementation
+	Order          SortOrder
+	RankingMode    RankingMode // 为 nil 时使用序数排名
+	UpdatePolicy   UpdatePolicy
+	TimestampMode  TimestampMode
+	MaxPlayers     int // 玩家数上限，0 表示使用注册表的上限，不能超过注册表的上限
+}
+
+// RegistryConfig 注册表配置
+type RegistryConfig struct {
+	MaxBoards          int // 排行榜数量上限，0 表示不限制
+	MaxPlayersPerBoard int // 每个排行榜的玩家数上限，0 表示不限制
+}
+
+// BoardInfo 注册表中一个排行榜的概况
+type BoardInfo struct {
+	Name    string
+	Config  BoardConfig // 创建时的配置，MaxPlayers 为实际生效的上限
+	Players int         // 当前玩家数
+}
+
+// Registry 按名称管理多个排行榜，例如按玩法、区服、赛季划分的排行榜，可以并发使用
+//
+// 删除排行榜只是把它从注册表中移除，已经取得该排行榜的调用方仍可继续读写，
+// 之后以同名创建的排行榜是一个新的空排行榜
+type Registry struct {
+	cfg RegistryConfig
+
+	mu     sync.RWMutex
+	boards map[string]*registeredBoard
+}
+
+type registeredBoard struct {
+	board LeaderboardService
+	cfg   BoardConfig
+}
+
+// NewRegistry 创建排行榜注册表
+func NewRegistry(cfg RegistryConfig) *Registry {
+	return &Registry{cfg: cfg, boards: make(map[string]*registeredBoard)}
+}
+
+// Create 按配置创建排行榜并以 name 注册
+// 名称已被占用时返回 ErrBoardExists，排行榜数量达到上限时返回 ErrTooManyBoards
+func (r *Registry) Create(name string, cfg BoardConfig) (LeaderboardService, error) {
+	if name == "" {
+		return nil, errors.New("leaderboard: board name must not be empty")
+	}
+	if limit := r.cfg.MaxPlayersPerBoard; limit > 0 {
+		if cfg.MaxPlayers > limit {
+			return nil, fmt.Errorf("leaderboard: board %q max players %d exceeds the limit of %d", name, cfg.MaxPlayers, limit)
+		}
+		if cfg.MaxPlayers <= 0 {
+			cfg.MaxPlayers = limit
+		}
+	}
+	if cfg.RankingMode == nil {
+		cfg.RankingMode = OrdinalRanking
+	}
+	opts := []Option{
+		WithOrder(cfg.Order),
+		WithRankingMode(cfg.RankingMode),
+		WithUpdatePolicy(cfg.UpdatePolicy),
+		WithTimestampMode(cfg.TimestampMode),
+		WithMaxPlayers(cfg.MaxPlayers),
+	}
+	var board LeaderboardService
+	switch cfg.Implementation {
+	case ImplSkipList:
+		board = NewLeaderboardSkipList(opts...)
+	case ImplLinkedList:
+		board = NewLeaderboardLinkedList(opts...)
+	default:
+		return nil, fmt.Errorf("leaderboard: board %q has unknown implementation %v", name, cfg.Implementation)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.boards[name]; exists {
+		return nil, fmt.Errorf("%w: %q", ErrBoardExists, name)
+	}
+	if r.cfg.MaxBoards > 0 && len(r.boards) >= r.cfg.MaxBoards {
+		return nil, fmt.Errorf("%w: limit is %d", ErrTooManyBoards, r.cfg.MaxBoards)
+	}
+	r.boards[name] = &registeredBoard{board: board, cfg: cfg}
+	return board, nil
+}
+
+// Get 按名称查找排行榜，不存在时返回 false
+func (r *Registry) Get(name string) (LeaderboardService, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rb, ok := r.boards[name]
+	if !ok {
+		return nil, false
+	}
+	return rb.board, true
+}
+
+// Delete 删除排行榜，不存在时返回 false
+func (r *Registry) Delete(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.boards[name]; !ok {
+		return false
+	}
+	delete(r.boards, name)
+	return true
+}
+
+// List 按名称顺序列出所有排行榜
+func (r *Registry) List() []BoardInfo {
+	r.mu.RLock()
+	infos := make([]BoardInfo, 0, len(r.boards))
+	boards := make([]LeaderboardService, 0, len(r.boards))
+	for name, rb := range r.boards {
+		infos = append(infos, BoardInfo{Name: name, Config: rb.cfg})
+		boards = append(boards, rb.board)
+	}
+	r.mu.RUnlock()
+
+	// 在注册表的锁之外统计玩家数，避免与排行榜的锁交叉
+	for i, board := range boards {
+		infos[i].Players = board.Len()
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Len 返回排行榜数量
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.boards)
+}
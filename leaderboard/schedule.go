@@ -0,0 +1,162 @@
+package leaderboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 赛季切换时间表
+type Schedule interface {
+	Next(t time.Time) time.Time // 返回严格晚于 t 的下一个切换时间
+}
+
+// cronSchedule 按 cron 表达式计算切换时间，各字段以位图表示允许的取值
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool // 日期和星期字段是否为 *，决定两者按“且”还是“或”组合
+	loc                           *time.Location
+}
+
+// cronField cron 表达式各字段的取值范围
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 和 7 都表示周日
+}
+
+// Daily 每天 0 点切换的时间表，loc 为 nil 时使用 UTC
+func Daily(loc *time.Location) Schedule {
+	s, _ := ParseCron("0 0 * * *", loc)
+	return s
+}
+
+// Weekly 每周 weekday 的 0 点切换的时间表，loc 为 nil 时使用 UTC
+func Weekly(weekday time.Weekday, loc *time.Location) Schedule {
+	s, _ := ParseCron(fmt.Sprintf("0 0 * * %d", weekday), loc)
+	return s
+}
+
+// ParseCron 解析标准的 5 字段 cron 表达式：分 时 日 月 星期，loc 为 nil 时使用 UTC
+//
+// 每个字段支持 *、单个值、范围 a-b、步长 */n 或 a-b/n，以及用逗号分隔的多项；
+// 星期取值 0-7，0 和 7 都表示周日。日期和星期都不为 * 时，满足其一即可，与 cron 的约定一致。
+// 另支持 @hourly、@daily、@weekly、@monthly 四个简写
+func ParseCron(spec string, loc *time.Location) (Schedule, error) {
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("leaderboard: cron spec %q must have %d fields", spec, len(cronFields))
+	}
+	var bits [5]uint64
+	for i, field := range fields {
+		b, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("leaderboard: cron spec %q: %v", spec, err)
+		}
+		bits[i] = b
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1 // 7 等同于 0
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	s := &cronSchedule{
+		minute: bits[0], hour: bits[1], dom: bits[2], month: bits[3], dow: bits[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+		loc: loc,
+	}
+	if s.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("leaderboard: cron spec %q never matches", spec)
+	}
+	return s, nil
+}
+
+// parseCronField 解析一个字段，返回允许取值的位图
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid %s field %q", f.name, part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid %s field %q", f.name, part)
+				}
+			} else if step > 1 {
+				hi = f.max // a/n 表示从 a 开始到最大值
+			}
+			if lo < f.min || hi > f.max || lo > hi {
+				return 0, fmt.Errorf("%s field %q out of range %d-%d", f.name, part, f.min, f.max)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next 从 t 之后的下一分钟开始，按月、日、时、分的顺序逐级跳到第一个匹配的时间
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	// 任何合法表达式在 5 年内都会匹配（2 月 29 日在闰年出现），超出说明表达式无法匹配，例如 2 月 30 日
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case s.month&(1<<uint(m)) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, s.loc)
+		case !s.dayMatches(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, s.loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, s.loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
@@ -0,0 +1,174 @@
+package leaderboard
+
+// scoreNode 分数索引的节点，每个不同的分数对应一个节点
+type scoreNode struct {
+	score   int          // 分数
+	count   int          // 持有该分数的玩家数
+	forward []*scoreNode // 各层的前向指针
+	span    []int        // 各层前向指针跨越的节点数
+}
+
+// scoreIndex 按排行榜排序方向排列的不同分数索引（带跨度的跳表）
+// 用于在 O(log n) 时间内统计比某个分数更优的不同分数个数，支撑密集排名
+type scoreIndex struct {
+	header *scoreNode
+	level  int                // 当前最大层数
+	length int                // 不同分数的个数
+	nodes  map[int]*scoreNode // 分数到节点的映射
+	better func(a, b int) bool
+}
+
+func newScoreIndex(better func(a, b int) bool) *scoreIndex {
+	return &scoreIndex{
+		header: &scoreNode{forward: make([]*scoreNode, MaxLevel), span: make([]int, MaxLevel)},
+		level:  1,
+		nodes:  make(map[int]*scoreNode),
+		better: better,
+	}
+}
+
+// add 记录一名玩家持有该分数，分数首次出现时插入新节点
+func (s *scoreIndex) add(score int) {
+	if node, exists := s.nodes[score]; exists {
+		node.count++
+		return
+	}
+
+	update := make([]*scoreNode, MaxLevel)
+	rank := make([]int, MaxLevel)
+	current := s.header
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for current.forward[i] != nil && s.better(current.forward[i].score, score) {
+			rank[i] += current.span[i]
+			current = current.forward[i]
+		}
+		update[i] = current
+	}
+
+	level := randomLevel()
+	if level > s.level {
+		for i := s.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = s.header
+			update[i].span[i] = s.length
+		}
+		s.level = level
+	}
+
+	node := &scoreNode{
+		score:   score,
+		count:   1,
+		forward: make([]*scoreNode, level),
+		span:    make([]int, level),
+	}
+	for i := 0; i < level; i++ {
+		node.forward[i] = update[i].forward[i]
+		update[i].forward[i] = node
+		node.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := level; i < s.level; i++ {
+		update[i].span[i]++
+	}
+
+	s.nodes[score] = node
+	s.length++
+}
+
+// remove 移除一名玩家持有的该分数，分数不再有玩家持有时删除节点
+func (s *scoreIndex) remove(score int) {
+	node, exists := s.nodes[score]
+	if !exists {
+		return
+	}
+	if node.count--; node.count > 0 {
+		return
+	}
+
+	update := make([]*scoreNode, MaxLevel)
+	current := s.header
+	for i := s.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && s.better(current.forward[i].score, score) {
+			current = current.forward[i]
+		}
+		update[i] = current
+	}
+	for i := 0; i < s.level; i++ {
+		if update[i].forward[i] == node {
+			update[i].span[i] += node.span[i] - 1
+			update[i].forward[i] = node.forward[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+	for s.level > 1 && s.header.forward[s.level-1] == nil {
+		s.level--
+	}
+
+	delete(s.nodes, score)
+	s.length--
+}
+
+// countBetter 统计严格优于 score 的不同分数个数，时间复杂度 O(log n)
+func (s *scoreIndex) countBetter(score int) int {
+	count := 0
+	current := s.header
+	for i := s.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && s.better(current.forward[i].score, score) {
+			count += current.span[i]
+			current = current.forward[i]
+		}
+	}
+	return count
+}
+
+// scoreByRank 获取第 rank 个不同分数（从1开始），超出范围返回 false
+func (s *scoreIndex) scoreByRank(rank int) (int, bool) {
+	if rank < 1 || rank > s.length {
+		return 0, false
+	}
+	traversed := 0
+	current := s.header
+	for i := s.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && traversed+current.span[i] <= rank {
+			traversed += current.span[i]
+			current = current.forward[i]
+		}
+		if traversed == rank {
+			return current.score, true
+		}
+	}
+	return 0, false
+}
+
+// load 用按排序方向排好序的不同分数及其玩家数重建索引，依次追加到各层尾部，时间复杂度 O(n)
+func (s *scoreIndex) load(scores, counts []int) {
+	s.header = &scoreNode{forward: make([]*scoreNode, MaxLevel), span: make([]int, MaxLevel)}
+	s.level = 1
+	s.nodes = make(map[int]*scoreNode, len(scores))
+
+	tails := make([]*scoreNode, MaxLevel) // 各层当前的最后一个节点
+	tailRanks := make([]int, MaxLevel)    // 各层最后一个节点的排名
+	for i := range tails {
+		tails[i] = s.header
+	}
+	for i, score := range scores {
+		level := randomLevel()
+		node := &scoreNode{score: score, count: counts[i], forward: make([]*scoreNode, level), span: make([]int, level)}
+		for lv := 0; lv < level; lv++ {
+			tails[lv].forward[lv] = node
+			tails[lv].span[lv] = i + 1 - tailRanks[lv]
+			tails[lv], tailRanks[lv] = node, i+1
+		}
+		s.level = max(s.level, level)
+		s.nodes[score] = node
+	}
+	s.length = len(scores)
+	// 各层最后一个节点的跨度为其之后的节点数
+	for lv := 0; lv < s.level; lv++ {
+		tails[lv].span[lv] = s.length - tailRanks[lv]
+	}
+}
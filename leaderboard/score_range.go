@@ -0,0 +1,61 @@
+package leaderboard
+
+import "math"
+
+// ScoreRange 表示分数区间，Min、Max 为分数的下界和上界，与排行榜的排序方向无关
+// 默认两端均为闭区间，设置 MinExclusive、MaxExclusive 可改为开区间
+type ScoreRange struct {
+	Min          int  // 分数下界
+	Max          int  // 分数上界
+	MinExclusive bool // 是否排除等于下界的分数
+	MaxExclusive bool // 是否排除等于上界的分数
+}
+
+// ScoreBetween 返回闭区间 [min, max]
+func ScoreBetween(min, max int) ScoreRange {
+	return ScoreRange{Min: min, Max: max}
+}
+
+// ScoreAtLeast 返回分数不低于 min 的区间
+func ScoreAtLeast(min int) ScoreRange {
+	return ScoreRange{Min: min, Max: math.MaxInt}
+}
+
+// ScoreAtMost 返回分数不高于 max 的区间
+func ScoreAtMost(max int) ScoreRange {
+	return ScoreRange{Min: math.MinInt, Max: max}
+}
+
+// Contains 判断分数是否落在区间内
+func (r ScoreRange) Contains(score int) bool {
+	return !r.belowMin(score) && !r.aboveMax(score)
+}
+
+// belowMin 判断分数是否低于区间下界
+func (r ScoreRange) belowMin(score int) bool {
+	return score < r.Min || (r.MinExclusive && score == r.Min)
+}
+
+// aboveMax 判断分数是否高于区间上界
+func (r ScoreRange) aboveMax(score int) bool {
+	return score > r.Max || (r.MaxExclusive && score == r.Max)
+}
+
+// rangeBounds 按排行榜排序方向返回区间的两个边界判断函数
+// beforeRange 判断分数是否排在区间之前，afterRange 判断分数是否排在区间之后
+func (o *options) rangeBounds(r ScoreRange) (beforeRange, afterRange func(score int) bool) {
+	if o.order == Ascending {
+		return r.belowMin, r.aboveMax
+	}
+	return r.aboveMax, r.belowMin
+}
+
+// pageRange 将区间内的序数名次 [first, last] 按 offset、limit 分页
+// offset 小于 0 按 0 处理，limit 小于等于 0 表示不限制数量
+func pageRange(first, last, offset, limit int) (int, int) {
+	first += max(0, offset)
+	if limit > 0 {
+		last = min(last, first+limit-1)
+	}
+	return first, last
+}
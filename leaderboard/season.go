@@ -0,0 +1,368 @@
+package leaderboard
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DecayRule 新赛季开始时由上赛季的分数计算结转分数，返回 false 表示该玩家不结转
+type DecayRule func(score int) (int, bool)
+
+// KeepPercent 结转上赛季分数的 percent%（向零取整），结转后为 0 的玩家不结转
+// percent 取值 [0, 100]，分数接近整数上限时也不会溢出
+func KeepPercent(percent int) DecayRule {
+	return func(score int) (int, bool) {
+		// 拆成整百和余数两部分分别计算，避免 score*percent 溢出；两部分同号，合起来仍是向零取整
+		carried := score/100*percent + score%100*percent/100
+		return carried, carried != 0
+	}
+}
+
+// SeasonConfig 赛季排行榜配置
+type SeasonConfig struct {
+	Schedule    Schedule                                // 赛季切换时间表，不能为 nil
+	Now         func() time.Time                        // 时钟，为 nil 时使用 time.Now，测试时可以注入
+	Decay       DecayRule                               // 新赛季的结转规则，为 nil 时新赛季从空榜开始
+	MaxArchives int                                     // 保留的已结束赛季数，超出时丢弃最早的；0 时使用 DefaultMaxArchives，负数表示不限制
+	NewBoard    func(opts ...Option) LeaderboardService // 每个赛季的排行榜构造函数，为 nil 时使用跳表实现
+}
+
+// DefaultMaxArchives 默认保留的已结束赛季数
+const DefaultMaxArchives = 16
+
+// SeasonInfo 赛季信息
+type SeasonInfo struct {
+	Number int       // 赛季序号，从 1 开始
+	Start  time.Time // 赛季开始时间
+	End    time.Time // 赛季结束时间，当前赛季为计划的结束时间
+}
+
+// SeasonalBoard 按时间表自动切换赛季的排行榜，实现 LeaderboardService，所有操作作用于当前赛季
+//
+// 每次操作前用时钟检查当前赛季是否已到结束时间，到期时先切换赛季再执行操作，不需要后台协程；
+// 长时间没有操作时错过的赛季合并为一个：当前赛季按计划的结束时间归档，结转规则只应用一次，
+// 新赛季从该时间开始，到当前时间之后的下一个切换时间结束，停机再久也只切换一次。
+// 结束的赛季冻结为只读的 Archive，切换赛季与写入互斥，归档后不会再有写入落到旧赛季上
+type SeasonalBoard struct {
+	cfg   SeasonConfig
+	opts  []Option
+	order options // 与各赛季排行榜相同的排序规则，用于整理结转的玩家
+
+	mu       sync.RWMutex
+	current  LeaderboardService
+	season   SeasonInfo
+	archives []*Archive // 按赛季顺序排列
+}
+
+// NewSeasonalBoard 创建赛季排行榜，第一个赛季从当前时间开始；opts 应用于每个赛季的排行榜
+func NewSeasonalBoard(cfg SeasonConfig, opts ...Option) *SeasonalBoard {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.MaxArchives == 0 {
+		cfg.MaxArchives = DefaultMaxArchives
+	}
+	if cfg.NewBoard == nil {
+		cfg.NewBoard = func(opts ...Option) LeaderboardService { return NewLeaderboardSkipList(opts...) }
+	}
+	now := cfg.Now()
+	return &SeasonalBoard{
+		cfg:     cfg,
+		opts:    opts,
+		order:   newOptions(opts),
+		current: cfg.NewBoard(opts...),
+		season:  SeasonInfo{Number: 1, Start: now, End: cfg.Schedule.Next(now)},
+	}
+}
+
+// acquire 切换到期的赛季后持有读锁返回当前赛季的排行榜，调用方用完后调用 release
+func (s *SeasonalBoard) acquire() LeaderboardService {
+	s.mu.RLock()
+	for s.due(s.cfg.Now()) {
+		s.mu.RUnlock()
+		s.mu.Lock()
+		if now := s.cfg.Now(); s.due(now) {
+			s.rollover(s.season.End, now)
+		}
+		s.mu.Unlock()
+		s.mu.RLock()
+	}
+	return s.current
+}
+
+func (s *SeasonalBoard) release() {
+	s.mu.RUnlock()
+}
+
+// due 判断当前赛季在 now 时是否已经结束，时间表没有下一个切换时间时赛季永不结束
+func (s *SeasonalBoard) due(now time.Time) bool {
+	return !s.season.End.IsZero() && !now.Before(s.season.End)
+}
+
+// rollover 在 end 时结束当前赛季，下一赛季从 end 开始，结束时间按时间表从 now 起计算，调用方需持有写锁
+func (s *SeasonalBoard) rollover(end, now time.Time) {
+	finished := s.season
+	finished.End = end
+	s.archives = append(s.archives, &Archive{SeasonInfo: finished, board: s.current})
+	if n := s.cfg.MaxArchives; n > 0 && len(s.archives) > n {
+		s.archives = append([]*Archive(nil), s.archives[len(s.archives)-n:]...)
+	}
+
+	next := s.cfg.NewBoard(s.opts...)
+	if s.cfg.Decay != nil {
+		s.carryOver(s.current, next)
+	}
+	s.current = next
+	s.season = SeasonInfo{Number: finished.Number + 1, Start: end, End: s.cfg.Schedule.Next(now)}
+}
+
+// carryOver 按结转规则把上赛季的玩家写入新赛季，玩家保留原时间戳，同分时仍按上赛季的先后排列
+func (s *SeasonalBoard) carryOver(prev, next LeaderboardService) {
+	var players []Player
+	prev.ForEach(func(p Player) bool {
+		if score, ok := s.cfg.Decay(p.Score); ok {
+			p.Score = score
+			players = append(players, p)
+		}
+		return true
+	})
+	// 结转可能让原本不同分的玩家同分，需要按排序规则重新排列
+	sort.Slice(players, func(i, j int) bool { return s.order.before(&players[i], &players[j]) })
+	if loader, ok := next.(BulkLoader); ok && loader.Load(players) == nil {
+		return
+	}
+	for _, p := range players {
+		next.UpdateScore(p.PlayerID, p.Score, p.Timestamp)
+	}
+}
+
+// Rollover 立即结束当前赛季，下一赛季的结束时间按时间表从现在起计算
+func (s *SeasonalBoard) Rollover() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.cfg.Now()
+	s.rollover(now, now)
+}
+
+// Season 返回当前赛季的信息
+func (s *SeasonalBoard) Season() SeasonInfo {
+	s.acquire()
+	defer s.release()
+
+	return s.season
+}
+
+// Archives 按赛季顺序返回保留的已结束赛季
+func (s *SeasonalBoard) Archives() []*Archive {
+	s.acquire()
+	defer s.release()
+
+	return append([]*Archive(nil), s.archives...)
+}
+
+// Archive 按赛季序号查找已结束的赛季，赛季未结束或已被丢弃时返回 false
+func (s *SeasonalBoard) Archive(number int) (*Archive, bool) {
+	s.acquire()
+	defer s.release()
+
+	// 归档按序号连续排列，第一个归档之前的已被丢弃
+	if len(s.archives) == 0 {
+		return nil, false
+	}
+	i := number - s.archives[0].Number
+	if i < 0 || i >= len(s.archives) {
+		return nil, false
+	}
+	return s.archives[i], true
+}
+
+// UpdateScore 更新当前赛季玩家分数
+func (s *SeasonalBoard) UpdateScore(playerID string, score int, timestamp time.Time) UpdateResult {
+	board := s.acquire()
+	defer s.release()
+
+	return board.UpdateScore(playerID, score, timestamp)
+}
+
+// IncrementScore 原子地增加当前赛季玩家分数
+func (s *SeasonalBoard) IncrementScore(playerID string, delta int, timestamp time.Time) RankInfo {
+	board := s.acquire()
+	defer s.release()
+
+	return board.IncrementScore(playerID, delta, timestamp)
+}
+
+// RemovePlayer 从当前赛季删除玩家
+func (s *SeasonalBoard) RemovePlayer(playerID string) bool {
+	board := s.acquire()
+	defer s.release()
+
+	return board.RemovePlayer(playerID)
+}
+
+// RemovePlayers 从当前赛季批量删除玩家
+func (s *SeasonalBoard) RemovePlayers(playerIDs ...string) int {
+	board := s.acquire()
+	defer s.release()
+
+	return board.RemovePlayers(playerIDs...)
+}
+
+// Reset 清空当前赛季，不影响已结束的赛季
+func (s *SeasonalBoard) Reset() {
+	board := s.acquire()
+	defer s.release()
+
+	board.Reset()
+}
+
+// GetPlayerRank 获取玩家在当前赛季的排名
+func (s *SeasonalBoard) GetPlayerRank(playerID string) (RankInfo, bool) {
+	board := s.acquire()
+	defer s.release()
+
+	return board.GetPlayerRank(playerID)
+}
+
+// GetTopN 获取当前赛季前 N 名
+func (s *SeasonalBoard) GetTopN(n int) []RankInfo {
+	board := s.acquire()
+	defer s.release()
+
+	return board.GetTopN(n)
+}
+
+// GetPlayerRankRange 获取玩家在当前赛季的周边排名
+func (s *SeasonalBoard) GetPlayerRankRange(playerID string, rangeN int) []RankInfo {
+	board := s.acquire()
+	defer s.release()
+
+	return board.GetPlayerRankRange(playerID, rangeN)
+}
+
+// GetByRank 获取当前赛季指定名次的玩家
+func (s *SeasonalBoard) GetByRank(rank int) (RankInfo, bool) {
+	board := s.acquire()
+	defer s.release()
+
+	return board.GetByRank(rank)
+}
+
+// GetRankRange 获取当前赛季名次区间内的玩家
+func (s *SeasonalBoard) GetRankRange(start, end int) []RankInfo {
+	board := s.acquire()
+	defer s.release()
+
+	return board.GetRankRange(start, end)
+}
+
+// Len 获取当前赛季的玩家总数
+func (s *SeasonalBoard) Len() int {
+	board := s.acquire()
+	defer s.release()
+
+	return board.Len()
+}
+
+// CountByScore 统计当前赛季分数区间内的玩家数
+func (s *SeasonalBoard) CountByScore(r ScoreRange) int {
+	board := s.acquire()
+	defer s.release()
+
+	return board.CountByScore(r)
+}
+
+// GetByScore 按名次顺序获取当前赛季分数区间内的玩家
+func (s *SeasonalBoard) GetByScore(r ScoreRange, offset, limit int) []RankInfo {
+	board := s.acquire()
+	defer s.release()
+
+	return board.GetByScore(r, offset, limit)
+}
+
+// GetPlayerPercentile 获取玩家在当前赛季击败的百分比
+func (s *SeasonalBoard) GetPlayerPercentile(playerID string) (float64, bool) {
+	board := s.acquire()
+	defer s.release()
+
+	return board.GetPlayerPercentile(playerID)
+}
+
+// GetTopPercent 获取当前赛季名次位于前 p% 的玩家
+func (s *SeasonalBoard) GetTopPercent(p float64) []RankInfo {
+	board := s.acquire()
+	defer s.release()
+
+	return board.GetTopPercent(p)
+}
+
+// ForEach 按名次顺序遍历当前赛季的玩家
+func (s *SeasonalBoard) ForEach(fn func(p Player) bool) {
+	board := s.acquire()
+	defer s.release()
+
+	board.ForEach(fn)
+}
+
+// Archive 已结束赛季的只读排行榜，只提供 LeaderboardReader 中的查询方法
+type Archive struct {
+	SeasonInfo
+	board LeaderboardService
+}
+
+// GetPlayerRank 获取玩家在该赛季的最终排名
+func (a *Archive) GetPlayerRank(playerID string) (RankInfo, bool) {
+	return a.board.GetPlayerRank(playerID)
+}
+
+// GetTopN 获取该赛季的最终前 N 名
+func (a *Archive) GetTopN(n int) []RankInfo {
+	return a.board.GetTopN(n)
+}
+
+// GetPlayerRankRange 获取玩家在该赛季的最终周边排名
+func (a *Archive) GetPlayerRankRange(playerID string, rangeN int) []RankInfo {
+	return a.board.GetPlayerRankRange(playerID, rangeN)
+}
+
+// GetByRank 获取该赛季指定名次的玩家
+func (a *Archive) GetByRank(rank int) (RankInfo, bool) {
+	return a.board.GetByRank(rank)
+}
+
+// GetRankRange 获取该赛季名次区间内的玩家
+func (a *Archive) GetRankRange(start, end int) []RankInfo {
+	return a.board.GetRankRange(start, end)
+}
+
+// Len 获取该赛季的玩家总数
+func (a *Archive) Len() int {
+	return a.board.Len()
+}
+
+// CountByScore 统计该赛季分数区间内的玩家数
+func (a *Archive) CountByScore(r ScoreRange) int {
+	return a.board.CountByScore(r)
+}
+
+// GetByScore 按名次顺序获取该赛季分数区间内的玩家
+func (a *Archive) GetByScore(r ScoreRange, offset, limit int) []RankInfo {
+	return a.board.GetByScore(r, offset, limit)
+}
+
+// GetPlayerPercentile 获取玩家在该赛季击败的百分比
+func (a *Archive) GetPlayerPercentile(playerID string) (float64, bool) {
+	return a.board.GetPlayerPercentile(playerID)
+}
+
+// GetTopPercent 获取该赛季名次位于前 p% 的玩家
+func (a *Archive) GetTopPercent(p float64) []RankInfo {
+	return a.board.GetTopPercent(p)
+}
+
+// ForEach 按名次顺序遍历该赛季的玩家
+func (a *Archive) ForEach(fn func(p Player) bool) {
+	a.board.ForEach(fn)
+}
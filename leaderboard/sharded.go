@@ -0,0 +1,741 @@
+package leaderboard
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ShardedBoard 分片排行榜，按玩家ID哈希把玩家分布到多个跳表分片上，每个分片有自己的锁
+//
+// 写入只锁玩家所在的分片，不同分片上的写入可以并行。查询对所有分片加读锁，在同一时刻的快照上计算：
+// 名次为各分片中排在玩家之前的玩家数之和，列表查询对各分片做多路归并，结果与单个跳表排行榜完全一致，
+// 包括同分时按时间戳、玩家ID的先后。密集排名所需的不同分数由所有分片共享的分数索引统计。
+//
+// 写操作返回的写入前后的名次在写入之后的同一快照上计算，名次变化只反映本次写入；
+// 名次本身可能已经包含其他分片上并发写入的影响。
+// 通过 WithEventBus 挂上事件总线后，有监听器期间写入对所有分片加写锁，在同一快照上计算事件，不同分片上的写入不再并行
+type ShardedBoard struct {
+	shards []*LeaderboardSkipList
+	opts   options
+	size   int64 // 玩家总数，用于在分片的写锁内检查玩家数上限
+
+	scoresMu sync.Mutex  // 不同分片的写入并发修改分数索引时加锁；查询时持有所有分片的读锁，不会有写入
+	scores   *scoreIndex // 所有分片的不同分数索引
+}
+
+// NewShardedBoard 创建有 n 个分片的排行榜，n 小于 1 时按 1 处理
+func NewShardedBoard(n int, opts ...Option) *ShardedBoard {
+	b := &ShardedBoard{opts: newOptions(opts)}
+	// 分片只负责存储，玩家数上限由分片排行榜统一检查
+	shardOpts := append(append([]Option{}, opts...), WithEventBus(nil), WithMaxPlayers(0))
+	for i := 0; i < max(1, n); i++ {
+		b.shards = append(b.shards, NewLeaderboardSkipList(shardOpts...))
+	}
+	b.scores = newScoreIndex(b.opts.scoreBetter)
+	return b
+}
+
+// shard 按玩家ID的 FNV-1a 哈希选择分片
+func (b *ShardedBoard) shard(playerID string) *LeaderboardSkipList {
+	h := uint32(2166136261)
+	for i := 0; i < len(playerID); i++ {
+		h ^= uint32(playerID[i])
+		h *= 16777619
+	}
+	return b.shards[h%uint32(len(b.shards))]
+}
+
+// rlock 按分片顺序对所有分片加读锁，得到一致的快照
+func (b *ShardedBoard) rlock() {
+	for _, sh := range b.shards {
+		sh.mu.RLock()
+	}
+}
+
+func (b *ShardedBoard) runlock() {
+	for _, sh := range b.shards {
+		sh.mu.RUnlock()
+	}
+}
+
+// write 加锁执行写入 fn，再在写入之后的同一快照上执行 result 计算返回结果，result 可以为 nil
+// 有事件监听器时对所有分片加写锁，fn 收到事件查询 src，写入、事件和结果都在同一快照上完成；
+// 否则写入只锁玩家所在的分片，src 为 nil，写入后对所有分片加读锁执行 result
+func (b *ShardedBoard) write(sh *LeaderboardSkipList, fn func(src eventSource), result func()) {
+	if b.opts.events.active() {
+		b.lock()
+		defer b.unlock()
+		fn(shardedEvents{b})
+		if result != nil {
+			result()
+		}
+		return
+	}
+	sh.mu.Lock()
+	fn(nil)
+	sh.mu.Unlock()
+	if result != nil {
+		b.rlock()
+		defer b.runlock()
+		result()
+	}
+}
+
+// begin 在写入前记录事件所需的排名，src 为 nil 时不产生事件
+func (b *ShardedBoard) begin(src eventSource, playerID string, next *Player) *changeRecorder {
+	if src == nil {
+		return nil
+	}
+	return b.opts.events.begin(src, playerID, next)
+}
+
+// UpdateScore 按更新策略更新分数，没有事件监听器时只锁玩家所在的分片
+// 写入前后的玩家记录在分片的写锁内取得，之后在所有分片的同一快照上分别计算两者的名次，见 rankAs
+func (b *ShardedBoard) UpdateScore(playerID string, score int, timestamp time.Time) UpdateResult {
+	sh := b.shard(playerID)
+	var old, cur Player
+	existed, rejected := false, false
+	res := UpdateResult{Rejected: true}
+	b.write(sh, func(src eventSource) {
+		var oldPlayer *Player
+		if node, exists := sh.playerMap[playerID]; exists {
+			old, oldPlayer, existed = *node.player, node.player, true
+		}
+		cur = old
+		newScore, write := b.opts.policyScore(oldPlayer, score)
+		if !write {
+			return
+		}
+		rec := b.begin(src, playerID, &Player{playerID, newScore, b.opts.writeTimestamp(oldPlayer, newScore, timestamp)})
+		rejected = !b.setScore(sh, playerID, newScore, timestamp)
+		rec.finish(src)
+		if !rejected {
+			cur = *sh.playerMap[playerID].player
+		}
+	}, func() {
+		if rejected {
+			return
+		}
+		var oldInfo RankInfo
+		if existed {
+			oldInfo = b.rankAs(&old)
+		}
+		res = newUpdateResult(oldInfo, existed, b.rankAs(&cur))
+	})
+	return res
+}
+
+// IncrementScore 原子地增加玩家分数，读取旧分数和写入新分数在分片的同一把写锁内完成
+func (b *ShardedBoard) IncrementScore(playerID string, delta int, timestamp time.Time) RankInfo {
+	sh := b.shard(playerID)
+	var cur Player
+	var info RankInfo
+	ok := false
+	b.write(sh, func(src eventSource) {
+		score := delta
+		var old *Player
+		if node, exists := sh.playerMap[playerID]; exists {
+			old = node.player
+			score += node.score
+		}
+		rec := b.begin(src, playerID, &Player{playerID, score, b.opts.writeTimestamp(old, score, timestamp)})
+		ok = b.setScore(sh, playerID, score, timestamp)
+		rec.finish(src)
+		if ok {
+			cur = *sh.playerMap[playerID].player
+		}
+	}, func() {
+		if ok {
+			info = b.rankAs(&cur)
+		}
+	})
+	return info
+}
+
+// setScore 在分片中写入玩家分数并同步分数索引，调用方需持有分片的写锁；排行榜已满导致新玩家未能上榜时返回 false
+func (b *ShardedBoard) setScore(sh *LeaderboardSkipList, playerID string, score int, timestamp time.Time) bool {
+	node, exists := sh.playerMap[playerID]
+	if !exists {
+		if n := atomic.AddInt64(&b.size, 1); b.opts.exceeds(int(n)) {
+			atomic.AddInt64(&b.size, -1)
+			return false
+		}
+	}
+	var oldScore int
+	if exists {
+		oldScore = node.score
+	}
+	sh.setScore(playerID, score, timestamp)
+
+	b.scoresMu.Lock()
+	if exists {
+		b.scores.remove(oldScore)
+	}
+	b.scores.add(score)
+	b.scoresMu.Unlock()
+	return true
+}
+
+// RemovePlayer 删除玩家，没有事件监听器时只锁玩家所在的分片
+func (b *ShardedBoard) RemovePlayer(playerID string) bool {
+	sh := b.shard(playerID)
+	removed := false
+	b.write(sh, func(src eventSource) {
+		node, exists := sh.playerMap[playerID]
+		if !exists {
+			return
+		}
+		rec := b.begin(src, playerID, nil)
+		sh.removePlayer(playerID)
+		rec.finish(src)
+		atomic.AddInt64(&b.size, -1)
+
+		b.scoresMu.Lock()
+		b.scores.remove(node.score)
+		b.scoresMu.Unlock()
+		removed = true
+	}, nil)
+	return removed
+}
+
+// RemovePlayers 批量删除玩家，逐个锁玩家所在的分片，返回实际删除的玩家数
+func (b *ShardedBoard) RemovePlayers(playerIDs ...string) int {
+	removed := 0
+	for _, playerID := range playerIDs {
+		if b.RemovePlayer(playerID) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// lock 按分片顺序对所有分片加写锁
+func (b *ShardedBoard) lock() {
+	for _, sh := range b.shards {
+		sh.mu.Lock()
+	}
+}
+
+func (b *ShardedBoard) unlock() {
+	for _, sh := range b.shards {
+		sh.mu.Unlock()
+	}
+}
+
+// Reset 清空所有分片
+func (b *ShardedBoard) Reset() {
+	b.lock()
+	defer b.unlock()
+
+	for _, sh := range b.shards {
+		sh.reset()
+	}
+	atomic.StoreInt64(&b.size, 0)
+	b.scores = newScoreIndex(b.opts.scoreBetter)
+}
+
+// Load 用已排好序的玩家替换排行榜内容，有序列表按分片拆分后仍然有序，各分片直接批量装载
+func (b *ShardedBoard) Load(players []Player) error {
+	if err := b.opts.checkSorted(players); err != nil {
+		return err
+	}
+	parts := make(map[*LeaderboardSkipList][]Player, len(b.shards))
+	var scores, counts []int
+	for _, p := range players {
+		sh := b.shard(p.PlayerID)
+		parts[sh] = append(parts[sh], p)
+		if n := len(scores); n > 0 && scores[n-1] == p.Score {
+			counts[n-1]++
+		} else {
+			scores = append(scores, p.Score)
+			counts = append(counts, 1)
+		}
+	}
+
+	b.lock()
+	defer b.unlock()
+
+	for _, sh := range b.shards {
+		sh.load(parts[sh])
+	}
+	atomic.StoreInt64(&b.size, int64(len(players)))
+	b.scores = newScoreIndex(b.opts.scoreBetter)
+	b.scores.load(scores, counts)
+	return nil
+}
+
+// 以下为查询的内部实现，调用方需持有所有分片的读锁
+
+// length 玩家总数
+func (b *ShardedBoard) length() int {
+	n := 0
+	for _, sh := range b.shards {
+		n += sh.length
+	}
+	return n
+}
+
+// countBefore 统计所有分片中排在 p 之前的玩家数
+func (b *ShardedBoard) countBefore(p *Player) int {
+	n := 0
+	for _, sh := range b.shards {
+		n += sh.countBefore(p)
+	}
+	return n
+}
+
+// countWhile 统计所有分片中排在最前面、连续满足条件的玩家数
+func (b *ShardedBoard) countWhile(pred func(score int) bool) int {
+	n := 0
+	for _, sh := range b.shards {
+		n += sh.countWhile(pred)
+	}
+	return n
+}
+
+// shardedCounter 分片排行榜的分数统计，按人数统计的结果为各分片之和，不同分数个数由共享的分数索引统计
+type shardedCounter struct {
+	b *ShardedBoard
+}
+
+func (c shardedCounter) CountBetter(score int) int {
+	return c.b.countWhile(func(s int) bool { return c.b.opts.scoreBetter(s, score) })
+}
+
+func (c shardedCounter) CountNotWorse(score int) int {
+	return c.b.countWhile(func(s int) bool { return !c.b.opts.scoreBetter(score, s) })
+}
+
+func (c shardedCounter) CountDistinctBetter(score int) int {
+	return c.b.scores.countBetter(score)
+}
+
+// replacedCounter 把玩家的当前记录替换为另一条记录后的分数统计
+// 分数的多重度由 CountNotWorse 与 CountBetter 之差得到，用于判断替换后不同分数的个数是否变化
+type replacedCounter struct {
+	c       ScoreCounter
+	better  func(a, b int) bool
+	cur     int  // 玩家当前的分数
+	hasCur  bool // 玩家当前是否在榜上
+	replace int  // 替换后的分数
+}
+
+// multiplicity 当前分数为 score 的玩家数
+func (c replacedCounter) multiplicity(score int) int {
+	return c.c.CountNotWorse(score) - c.c.CountBetter(score)
+}
+
+func (c replacedCounter) CountBetter(score int) int {
+	n := c.c.CountBetter(score)
+	if c.hasCur && c.better(c.cur, score) {
+		n--
+	}
+	if c.better(c.replace, score) {
+		n++
+	}
+	return n
+}
+
+func (c replacedCounter) CountNotWorse(score int) int {
+	n := c.c.CountNotWorse(score)
+	if c.hasCur && !c.better(score, c.cur) {
+		n--
+	}
+	if !c.better(score, c.replace) {
+		n++
+	}
+	return n
+}
+
+func (c replacedCounter) CountDistinctBetter(score int) int {
+	n := c.c.CountDistinctBetter(score)
+	if c.hasCur && c.cur == c.replace {
+		return n
+	}
+	if c.hasCur && c.better(c.cur, score) && c.multiplicity(c.cur) == 1 {
+		n-- // 玩家是当前分数的唯一持有者，替换后该分数消失
+	}
+	if c.better(c.replace, score) && c.multiplicity(c.replace) == 0 {
+		n++ // 替换后的分数是新出现的分数
+	}
+	return n
+}
+
+// rankAs 计算玩家的记录为 p 时在当前快照中的排名信息，其他玩家保持不变，调用方需持有所有分片的读锁
+// 写操作用它在同一快照上计算写入前后的名次，两次计算面对相同的其他玩家，名次变化只反映本次写入，
+// 不会混入在分片写锁释放后、取得快照前其他分片上的并发写入
+func (b *ShardedBoard) rankAs(p *Player) RankInfo {
+	c := replacedCounter{c: shardedCounter{b}, better: b.opts.scoreBetter, replace: p.Score}
+	if node, exists := b.find(p.PlayerID); exists {
+		c.cur, c.hasCur = node.score, true
+	}
+	// countBefore 不统计玩家自己的当前记录
+	return RankInfo{p.PlayerID, p.Score, b.opts.rankingMode.Rank(c, b.countBefore(p)+1, p.Score)}
+}
+
+// rankInfo 生成节点按排名策略计算的排名信息，ordinal 为节点的全局序数排名
+func (b *ShardedBoard) rankInfo(node *Node, ordinal int) RankInfo {
+	rank := b.opts.rankingMode.Rank(shardedCounter{b}, ordinal, node.score)
+	return RankInfo{node.player.PlayerID, node.score, rank}
+}
+
+// shardedEvents 分片排行榜的事件查询，调用方需持有所有分片的锁
+type shardedEvents struct {
+	b *ShardedBoard
+}
+
+func (e shardedEvents) info(playerID string) (RankInfo, int, bool) {
+	node, exists := e.b.find(playerID)
+	if !exists {
+		return RankInfo{}, 0, false
+	}
+	ordinal := e.b.countBefore(node.player) + 1
+	return e.b.rankInfo(node, ordinal), ordinal, true
+}
+
+func (e shardedEvents) infoAt(ordinal int) (RankInfo, bool) {
+	node := e.b.locate(ordinal)
+	if node == nil {
+		return RankInfo{}, false
+	}
+	return e.b.rankInfo(node, ordinal), true
+}
+
+func (e shardedEvents) countBefore(p *Player) int {
+	return e.b.countBefore(p)
+}
+
+func (e shardedEvents) size() int {
+	return e.b.length()
+}
+
+// find 查找玩家所在的节点
+func (b *ShardedBoard) find(playerID string) (*Node, bool) {
+	node, exists := b.shard(playerID).playerMap[playerID]
+	return node, exists
+}
+
+// locate 查找全局序数排名为 ordinal 的节点，超出范围返回 nil
+// 节点的全局排名随其在分片内的排名单调递增，在每个分片内二分查找，时间复杂度 O(分片数² × log² n)
+func (b *ShardedBoard) locate(ordinal int) *Node {
+	if ordinal < 1 || ordinal > b.length() {
+		return nil
+	}
+	for _, sh := range b.shards {
+		i := sort.Search(sh.length, func(i int) bool {
+			return b.countBefore(sh.getNodeByRank(i+1).player)+1 >= ordinal
+		})
+		if i < sh.length {
+			if node := sh.getNodeByRank(i + 1); b.countBefore(node.player)+1 == ordinal {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// startsAt 返回各分片中第一个不排在 p 之前的节点，从这些节点开始归并即从 p 的位置开始遍历全局名次
+func (b *ShardedBoard) startsAt(p *Player) []*Node {
+	starts := make([]*Node, len(b.shards))
+	for i, sh := range b.shards {
+		starts[i] = sh.getNodeByRank(sh.countBefore(p) + 1)
+	}
+	return starts
+}
+
+// heads 返回各分片的第一名
+func (b *ShardedBoard) heads() []*Node {
+	starts := make([]*Node, len(b.shards))
+	for i, sh := range b.shards {
+		starts[i] = sh.header.forward[0]
+	}
+	return starts
+}
+
+// nodeHeap 多路归并使用的最小堆，堆顶为排名最靠前的节点
+type nodeHeap struct {
+	nodes []*Node
+	opts  *options
+}
+
+func (h *nodeHeap) Len() int           { return len(h.nodes) }
+func (h *nodeHeap) Less(i, j int) bool { return h.opts.before(h.nodes[i].player, h.nodes[j].player) }
+func (h *nodeHeap) Swap(i, j int)      { h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i] }
+func (h *nodeHeap) Push(x interface{}) { h.nodes = append(h.nodes, x.(*Node)) }
+func (h *nodeHeap) Pop() interface{} {
+	node := h.nodes[len(h.nodes)-1]
+	h.nodes = h.nodes[:len(h.nodes)-1]
+	return node
+}
+
+// merge 从各分片的起始节点开始按名次顺序多路归并，fn 返回 false 时停止
+func (b *ShardedBoard) merge(starts []*Node, fn func(node *Node) bool) {
+	h := &nodeHeap{opts: &b.opts}
+	for _, node := range starts {
+		if node != nil {
+			h.nodes = append(h.nodes, node)
+		}
+	}
+	heap.Init(h)
+	for h.Len() > 0 {
+		node := h.nodes[0]
+		if !fn(node) {
+			return
+		}
+		if next := node.forward[0]; next != nil {
+			h.nodes[0] = next
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+}
+
+// rankRange 获取全局名次区间内的玩家，调用方需保证 start、end 已截断到合法范围
+// 定位起始节点后从各分片的对应位置归并
+func (b *ShardedBoard) rankRange(start, end int) []RankInfo {
+	first := b.locate(start)
+	if first == nil || start > end {
+		return nil
+	}
+	var res []RankInfo
+	b.merge(b.startsAt(first.player), func(node *Node) bool {
+		res = append(res, RankInfo{node.player.PlayerID, node.score, start + len(res)})
+		return start+len(res) <= end
+	})
+	return applyRanking(b.opts.rankingMode, shardedCounter{b}, res)
+}
+
+// GetPlayerRank 获取玩家排名，序数名次为各分片中排在玩家之前的玩家数之和加一
+func (b *ShardedBoard) GetPlayerRank(playerID string) (RankInfo, bool) {
+	b.rlock()
+	defer b.runlock()
+
+	if node, exists := b.find(playerID); exists {
+		return b.rankInfo(node, b.countBefore(node.player)+1), true
+	}
+	return RankInfo{}, false
+}
+
+// GetTopN 从各分片的第一名开始多路归并，取前 n 名
+func (b *ShardedBoard) GetTopN(n int) []RankInfo {
+	b.rlock()
+	defer b.runlock()
+
+	var res []RankInfo
+	if n > 0 {
+		b.merge(b.heads(), func(node *Node) bool {
+			res = append(res, RankInfo{node.player.PlayerID, node.score, len(res) + 1})
+			return len(res) < n
+		})
+	}
+	return applyRanking(b.opts.rankingMode, shardedCounter{b}, res)
+}
+
+// GetPlayerRankRange 获取周边排名
+// 全局排在玩家之前的 rangeN 名一定是各自分片内紧挨在玩家之前的 rangeN 名以内，
+// 因此从各分片中玩家之前第 rangeN 名的位置开始归并，保留玩家之前的最后 rangeN 名和之后的 rangeN 名
+func (b *ShardedBoard) GetPlayerRankRange(playerID string, rangeN int) []RankInfo {
+	b.rlock()
+	defer b.runlock()
+
+	target, exists := b.find(playerID)
+	if !exists || rangeN < 0 {
+		return nil // rangeN 为负时区间为空，与其他实现一致
+	}
+	starts := make([]*Node, len(b.shards))
+	ordinal := 1
+	for i, sh := range b.shards {
+		before := sh.countBefore(target.player)
+		ordinal += before
+		starts[i] = sh.getNodeByRank(max(0, before-rangeN) + 1)
+	}
+
+	var res []RankInfo
+	after := -1 // 已经取到的玩家之后的人数，-1 表示还没有遇到玩家
+	b.merge(starts, func(node *Node) bool {
+		res = append(res, RankInfo{PlayerID: node.player.PlayerID, Score: node.score})
+		switch {
+		case node == target:
+			after = 0
+		case after >= 0:
+			after++
+		}
+		return after < rangeN
+	})
+	// 去掉玩家之前多余的玩家
+	res = res[max(0, len(res)-after-1-rangeN):]
+	first := ordinal - (len(res) - after - 1)
+	for i := range res {
+		res[i].Rank = first + i
+	}
+	return applyRanking(b.opts.rankingMode, shardedCounter{b}, res)
+}
+
+// GetByRank 获取指定名次的玩家，名次从1开始，超出范围返回空的排名信息和 false
+func (b *ShardedBoard) GetByRank(rank int) (RankInfo, bool) {
+	b.rlock()
+	defer b.runlock()
+
+	if node := b.locate(rank); node != nil {
+		return b.rankInfo(node, rank), true
+	}
+	return RankInfo{}, false
+}
+
+// GetRankRange 获取名次区间 [start, end] 内的玩家，区间两端会被截断到 [1, 玩家总数]，区间为空时返回 nil
+func (b *ShardedBoard) GetRankRange(start, end int) []RankInfo {
+	b.rlock()
+	defer b.runlock()
+
+	return b.rankRange(max(1, start), min(b.length(), end))
+}
+
+// Len 获取排行榜玩家总数
+func (b *ShardedBoard) Len() int {
+	b.rlock()
+	defer b.runlock()
+
+	return b.length()
+}
+
+// scoreRankRange 计算分数区间对应的全局序数名次区间 [first, last]，区间为空时 first > last
+func (b *ShardedBoard) scoreRankRange(r ScoreRange) (int, int) {
+	beforeRange, afterRange := b.opts.rangeBounds(r)
+	first := b.countWhile(beforeRange) + 1
+	last := b.countWhile(func(score int) bool { return !afterRange(score) })
+	return first, last
+}
+
+// CountByScore 统计分数区间内的玩家数
+func (b *ShardedBoard) CountByScore(r ScoreRange) int {
+	b.rlock()
+	defer b.runlock()
+
+	first, last := b.scoreRankRange(r)
+	return max(0, last-first+1)
+}
+
+// GetByScore 按名次顺序获取分数区间内的玩家，跳过前 offset 名，最多返回 limit 名，limit 小于等于 0 表示不限制
+func (b *ShardedBoard) GetByScore(r ScoreRange, offset, limit int) []RankInfo {
+	b.rlock()
+	defer b.runlock()
+
+	first, last := b.scoreRankRange(r)
+	first, last = pageRange(first, last, offset, limit)
+	if first > last {
+		return nil
+	}
+	return b.rankRange(first, last)
+}
+
+// GetPlayerPercentile 获取玩家击败的百分比，计算规则见 percentile
+func (b *ShardedBoard) GetPlayerPercentile(playerID string) (float64, bool) {
+	b.rlock()
+	defer b.runlock()
+
+	if node, exists := b.find(playerID); exists {
+		info := b.rankInfo(node, b.countBefore(node.player)+1)
+		return percentile(info.Rank, percentileBase(b.opts.rankingMode, b.length(), b.scores.length)), true
+	}
+	return 0, false
+}
+
+// GetTopPercent 获取名次位于前 p% 的玩家，计算规则见 topPercentCutoff
+// 名次随序数名次单调不减，从第一名开始归并，遇到第一个超过名次上限的玩家即停止
+func (b *ShardedBoard) GetTopPercent(p float64) []RankInfo {
+	b.rlock()
+	defer b.runlock()
+
+	cutoff := topPercentCutoff(p, percentileBase(b.opts.rankingMode, b.length(), b.scores.length))
+	if cutoff == 0 {
+		return nil
+	}
+	counter := newCachedCounter(shardedCounter{b})
+	var res []RankInfo
+	b.merge(b.heads(), func(node *Node) bool {
+		rank := b.opts.rankingMode.Rank(counter, len(res)+1, node.score)
+		if rank > cutoff {
+			return false
+		}
+		res = append(res, RankInfo{node.player.PlayerID, node.score, rank})
+		return true
+	})
+	return res
+}
+
+// ForEach 按名次顺序遍历玩家（多路归并），fn 返回 false 时停止
+func (b *ShardedBoard) ForEach(fn func(p Player) bool) {
+	b.rlock()
+	defer b.runlock()
+
+	b.merge(b.heads(), func(node *Node) bool {
+		return fn(*node.player)
+	})
+}
+
+// GetDensePlayerRank 获取玩家密集排名（共享的不同分数索引）
+func (b *ShardedBoard) GetDensePlayerRank(playerID string) RankInfo {
+	b.rlock()
+	defer b.runlock()
+
+	if node, exists := b.find(playerID); exists {
+		return RankInfo{playerID, node.score, b.scores.countBetter(node.score) + 1}
+	}
+	return RankInfo{}
+}
+
+// GetDenseTopN 获取TopN（多路归并）
+func (b *ShardedBoard) GetDenseTopN(n int) []RankInfo {
+	b.rlock()
+	defer b.runlock()
+
+	var res []RankInfo
+	if n > 0 {
+		b.merge(b.heads(), func(node *Node) bool {
+			res = append(res, RankInfo{node.player.PlayerID, node.score, len(res) + 1})
+			return len(res) < n
+		})
+	}
+	return applyRanking(DenseRanking, shardedCounter{b}, res)
+}
+
+// GetDensePlayerRankRange 获取周边排名，返回密集排名在指定玩家密集排名前后各 rangeN 名以内的所有玩家
+// 第 start 个不同分数的第一名玩家为起始节点，从各分片的对应位置归并
+func (b *ShardedBoard) GetDensePlayerRankRange(playerID string, rangeN int) []RankInfo {
+	b.rlock()
+	defer b.runlock()
+
+	node, exists := b.find(playerID)
+	if !exists {
+		return nil
+	}
+	currentRank := b.scores.countBetter(node.score) + 1
+	start := max(1, currentRank-rangeN)
+	end := min(b.length(), currentRank+rangeN)
+	if rangeN < 0 || start > end {
+		return nil
+	}
+
+	startScore, _ := b.scores.scoreByRank(start)
+	first := b.locate(shardedCounter{b}.CountBetter(startScore) + 1)
+	if first == nil {
+		return nil
+	}
+	var res []RankInfo
+	playerRank := start
+	b.merge(b.startsAt(first.player), func(node *Node) bool {
+		if len(res) > 0 && res[len(res)-1].Score != node.score {
+			playerRank++
+		}
+		if playerRank > end {
+			return false
+		}
+		res = append(res, RankInfo{node.player.PlayerID, node.score, playerRank})
+		return true
+	})
+	return res
+}
@@ -2,6 +2,7 @@ package leaderboard
 
 import (
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 )
@@ -17,6 +18,7 @@ type Node struct {
 	score     int       // 玩家分数
 	timestamp time.Time // 玩家得分时间戳
 	forward   []*Node   // 各层的前向指针
+	span      []int     // 各层前向指针跨越的节点数，用于 O(log n) 计算排名
 }
 
 // LeaderboardSkipList 结构体表示使用跳表实现的排行榜
@@ -25,16 +27,25 @@ type LeaderboardSkipList struct {
 	mu        sync.RWMutex
 	header    *Node            // 头节点
 	level     int              // 当前最大层数
+	length    int              // 跳表节点总数
 	playerMap map[string]*Node // 玩家ID到节点的映射
+	scores    *scoreIndex      // 不同分数索引，用于 O(log n) 计算密集排名
+	opts      options          // 排行榜配置
 }
 
-func NewLeaderboardSkipList() *LeaderboardSkipList {
-	header := &Node{forward: make([]*Node, MaxLevel)}
-	return &LeaderboardSkipList{
+func NewLeaderboardSkipList(opts ...Option) *LeaderboardSkipList {
+	header := &Node{
+		forward: make([]*Node, MaxLevel),
+		span:    make([]int, MaxLevel),
+	}
+	l := &LeaderboardSkipList{
 		header:    header,
 		level:     1,
 		playerMap: make(map[string]*Node),
+		opts:      newOptions(opts),
 	}
+	l.scores = newScoreIndex(l.opts.scoreBetter)
+	return l
 }
 
 // 生成随机层数
@@ -46,57 +57,196 @@ func randomLevel() int {
 }
 
 // UpdateScore 更新分数,跳表插入
-// 如果玩家已经存在于排行榜中，先删除旧记录，然后插入新记录，保持跳表的有序性
-func (l *LeaderboardSkipList) UpdateScore(playerID string, score int, timestamp time.Time) {
+// 按更新策略计算新分数，如果玩家已经存在于排行榜中，先删除旧记录，然后插入新记录，保持跳表的有序性
+func (l *LeaderboardSkipList) UpdateScore(playerID string, score int, timestamp time.Time) UpdateResult {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	// 删除旧记录
+	var old RankInfo
+	var oldPlayer *Player
+	node, existed := l.playerMap[playerID]
+	if existed {
+		oldPlayer = node.player
+		old = l.rankInfo(node, l.getRank(node))
+	}
+	if !existed && l.opts.exceeds(l.length+1) {
+		return UpdateResult{Rejected: true}
+	}
+
+	if newScore, write := l.opts.policyScore(oldPlayer, score); write {
+		next := &Player{playerID, newScore, l.opts.writeTimestamp(oldPlayer, newScore, timestamp)}
+		rec := l.opts.events.begin(skipListEvents{l}, playerID, next)
+		node = l.setScore(playerID, newScore, timestamp)
+		rec.finish(skipListEvents{l})
+	}
+	return newUpdateResult(old, existed, l.rankInfo(node, l.getRank(node)))
+}
+
+// IncrementScore 原子地增加玩家分数并返回新的排名信息
+// 玩家不存在时以 0 分为基础；读取旧分数和写入新分数在同一把写锁内完成
+func (l *LeaderboardSkipList) IncrementScore(playerID string, delta int, timestamp time.Time) RankInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	score := delta
+	var old *Player
+	node, exists := l.playerMap[playerID]
+	if exists {
+		old = node.player
+		score += node.score
+	}
+	if !exists && l.opts.exceeds(l.length+1) {
+		return RankInfo{}
+	}
+	rec := l.opts.events.begin(skipListEvents{l}, playerID, &Player{playerID, score, l.opts.writeTimestamp(old, score, timestamp)})
+	node = l.setScore(playerID, score, timestamp)
+	rec.finish(skipListEvents{l})
+	return l.rankInfo(node, l.getRank(node))
+}
+
+// 写入玩家分数（内部使用），调用方需持有写锁
+// 先删除旧记录，再按排序规则插入新记录，返回玩家所在的跳表节点
+func (l *LeaderboardSkipList) setScore(playerID string, score int, timestamp time.Time) *Node {
+	var old *Player
 	if node, exists := l.playerMap[playerID]; exists {
-		l.deleteNode(node)
-		delete(l.playerMap, playerID)
+		old = node.player
+	}
+	timestamp = l.opts.writeTimestamp(old, score, timestamp)
+	if old != nil && old.Score == score && old.Timestamp.Equal(timestamp) {
+		return l.playerMap[playerID] // 分数和时间戳都没有变化，位置不变
 	}
 
-	// 创建新节点
-	newNode := &Node{
-		player:    &Player{playerID, score, timestamp},
-		score:     score,
-		timestamp: timestamp,
-		forward:   make([]*Node, randomLevel()),
+	// 删除旧记录
+	l.removePlayer(playerID)
+
+	node := l.insertNode(&Player{playerID, score, timestamp})
+	l.playerMap[playerID] = node
+	return node
+}
+
+// RemovePlayer 删除玩家（跳表按排序规则定位删除）
+// 玩家存在时同时从跳表和映射中删除并返回 true，否则返回 false
+func (l *LeaderboardSkipList) RemovePlayer(playerID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.removeWithEvents(playerID)
+}
+
+// RemovePlayers 批量删除玩家，只加一次锁，返回实际删除的玩家数
+func (l *LeaderboardSkipList) RemovePlayers(playerIDs ...string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed := 0
+	for _, playerID := range playerIDs {
+		if l.removeWithEvents(playerID) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Reset 清空排行榜，重建头节点
+func (l *LeaderboardSkipList) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.reset()
+}
+
+// 清空跳表（内部使用），调用方需持有写锁
+func (l *LeaderboardSkipList) reset() {
+	l.header = &Node{
+		forward: make([]*Node, MaxLevel),
+		span:    make([]int, MaxLevel),
+	}
+	l.level = 1
+	l.length = 0
+	l.playerMap = make(map[string]*Node)
+	l.scores = newScoreIndex(l.opts.scoreBetter)
+}
+
+// 删除玩家并产生排名变化事件（内部使用），调用方需持有写锁
+func (l *LeaderboardSkipList) removeWithEvents(playerID string) bool {
+	rec := l.opts.events.begin(skipListEvents{l}, playerID, nil)
+	removed := l.removePlayer(playerID)
+	rec.finish(skipListEvents{l})
+	return removed
+}
+
+// 删除玩家（内部使用），调用方需持有写锁
+func (l *LeaderboardSkipList) removePlayer(playerID string) bool {
+	node, exists := l.playerMap[playerID]
+	if !exists {
+		return false
 	}
+	l.deleteNode(node)
+	delete(l.playerMap, playerID)
+	return true
+}
 
-	// 查找插入位置
+// 插入节点（内部使用）
+// 从最高层开始查找插入位置，同时用 rank 记录每一层走过的节点数，插入后据此修正新节点和前驱节点的跨度
+func (l *LeaderboardSkipList) insertNode(player *Player) *Node {
 	update := make([]*Node, MaxLevel) // 记录每一层在插入新节点时，需要更新其 forward 指针的前一个节点
+	rank := make([]int, MaxLevel)     // 记录每一层 update 节点的排名
 	current := l.header
 	for i := l.level - 1; i >= 0; i-- {
-		for current.forward[i] != nil && (current.forward[i].score > score || (current.forward[i].score == score && current.forward[i].timestamp.Before(timestamp))) {
+		if i < l.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for current.forward[i] != nil && l.opts.before(current.forward[i].player, player) {
+			rank[i] += current.span[i]
 			current = current.forward[i]
 		}
 		update[i] = current
 	}
 
-	// 插入新节点并更新各层指针
-	for i := 0; i < len(newNode.forward) && update[i] != nil; i++ {
+	// 新节点层数超过当前最大层数时，新增的层由头节点直接指向新节点
+	level := randomLevel()
+	if level > l.level {
+		for i := l.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = l.header
+			update[i].span[i] = l.length
+		}
+		l.level = level
+	}
+
+	newNode := &Node{
+		player:    player,
+		score:     player.Score,
+		timestamp: player.Timestamp,
+		forward:   make([]*Node, level),
+		span:      make([]int, level),
+	}
+
+	// 插入新节点并更新各层指针和跨度
+	for i := 0; i < level; i++ {
 		newNode.forward[i] = update[i].forward[i]
 		update[i].forward[i] = newNode
+		newNode.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
 	}
-
-	// 更新当前最大层数
-	if len(newNode.forward) > l.level {
-		l.level = len(newNode.forward)
+	// 新节点没有达到的层，前驱节点的跨度多了一个节点
+	for i := level; i < l.level; i++ {
+		update[i].span[i]++
 	}
 
-	l.playerMap[playerID] = newNode
+	l.length++
+	l.scores.add(player.Score)
+	return newNode
 }
 
 // 删除节点（内部使用）
-// 从跳表的最高层开始，逐层查找要删除的节点，记录每一层需要更新的前一个节点在 update 切片中。
-// 遍历每一层，将前一个节点的 forward 指针指向要删除节点的下一个节点，从而将该节点从跳表中移除。
+// 从跳表的最高层开始，按排序规则逐层查找要删除的节点，记录每一层需要更新的前一个节点在 update 切片中。
+// 遍历每一层，将前一个节点的 forward 指针指向要删除节点的下一个节点并合并跨度，从而将该节点从跳表中移除。
 func (l *LeaderboardSkipList) deleteNode(node *Node) {
 	update := make([]*Node, MaxLevel)
 	current := l.header
 	for i := l.level - 1; i >= 0; i-- {
-		for current.forward[i] != nil && current.forward[i] != node {
+		for current.forward[i] != nil && l.opts.before(current.forward[i].player, node.player) {
 			current = current.forward[i]
 		}
 		update[i] = current
@@ -104,26 +254,151 @@ func (l *LeaderboardSkipList) deleteNode(node *Node) {
 
 	for i := 0; i < l.level; i++ {
 		if update[i].forward[i] == node {
+			update[i].span[i] += node.span[i] - 1
 			update[i].forward[i] = node.forward[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+
+	// 删除后最高层可能为空，降低当前最大层数
+	for l.level > 1 && l.header.forward[l.level-1] == nil {
+		l.level--
+	}
+	l.length--
+	l.scores.remove(node.score)
+}
+
+// 计算节点排名（内部使用），沿各层累加跨度，时间复杂度 O(log n)
+func (l *LeaderboardSkipList) getRank(node *Node) int {
+	rank := 0
+	current := l.header
+	for i := l.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && !l.opts.before(node.player, current.forward[i].player) {
+			rank += current.span[i]
+			current = current.forward[i]
+		}
+		if current == node {
+			return rank
+		}
+	}
+	return 0
+}
+
+// 统计排在最前面、连续满足条件的节点数（内部使用）
+// pred 必须对排序靠前的分数成立、对靠后的分数不成立，沿各层累加跨度，时间复杂度 O(log n)
+func (l *LeaderboardSkipList) countWhile(pred func(score int) bool) int {
+	count := 0
+	current := l.header
+	for i := l.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && pred(current.forward[i].score) {
+			count += current.span[i]
+			current = current.forward[i]
+		}
+	}
+	return count
+}
+
+// 统计除 p 本人外排在 p 之前的节点数（内部使用），沿各层累加跨度，时间复杂度 O(log n)
+// p 不必在跳表中；玩家自己的旧记录排在 p 之前时不计入
+func (l *LeaderboardSkipList) countBefore(p *Player) int {
+	count := 0
+	current := l.header
+	for i := l.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && l.opts.before(current.forward[i].player, p) {
+			count += current.span[i]
+			current = current.forward[i]
+		}
+	}
+	if node, exists := l.playerMap[p.PlayerID]; exists && l.opts.before(node.player, p) {
+		count--
+	}
+	return count
+}
+
+// 按排名查找节点（内部使用），排名从1开始，超出范围返回 nil
+func (l *LeaderboardSkipList) getNodeByRank(rank int) *Node {
+	if rank < 1 || rank > l.length {
+		return nil
+	}
+	traversed := 0
+	current := l.header
+	for i := l.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && traversed+current.span[i] <= rank {
+			traversed += current.span[i]
+			current = current.forward[i]
+		}
+		if traversed == rank {
+			return current
 		}
 	}
+	return nil
+}
+
+// 生成节点按排名策略计算的排名信息（内部使用），ordinal 为节点的序数排名
+func (l *LeaderboardSkipList) rankInfo(node *Node, ordinal int) RankInfo {
+	rank := l.opts.rankingMode.Rank(skipListCounter{l}, ordinal, node.score)
+	return RankInfo{node.player.PlayerID, node.score, rank}
+}
+
+// skipListCounter 跳表实现的分数统计，调用方需持有锁
+type skipListCounter struct {
+	l *LeaderboardSkipList
+}
+
+// CountBetter 统计分数严格更优的节点数，时间复杂度 O(log n)
+func (c skipListCounter) CountBetter(score int) int {
+	return c.l.countWhile(func(s int) bool { return c.l.opts.scoreBetter(s, score) })
+}
+
+// CountNotWorse 统计分数不差于 score 的节点数，时间复杂度 O(log n)
+func (c skipListCounter) CountNotWorse(score int) int {
+	return c.l.countWhile(func(s int) bool { return !c.l.opts.scoreBetter(score, s) })
+}
+
+// CountDistinctBetter 通过不同分数索引统计分数严格更优的不同分数个数，时间复杂度 O(log n)
+func (c skipListCounter) CountDistinctBetter(score int) int {
+	return c.l.scores.countBetter(score)
+}
+
+// skipListEvents 跳表实现的事件查询，调用方需持有锁
+type skipListEvents struct {
+	l *LeaderboardSkipList
+}
+
+func (e skipListEvents) info(playerID string) (RankInfo, int, bool) {
+	node, exists := e.l.playerMap[playerID]
+	if !exists {
+		return RankInfo{}, 0, false
+	}
+	ordinal := e.l.getRank(node)
+	return e.l.rankInfo(node, ordinal), ordinal, true
+}
+
+func (e skipListEvents) infoAt(ordinal int) (RankInfo, bool) {
+	node := e.l.getNodeByRank(ordinal)
+	if node == nil {
+		return RankInfo{}, false
+	}
+	return e.l.rankInfo(node, ordinal), true
 }
 
-// GetPlayerRank 获取玩家排名（跳表遍历计算）
+func (e skipListEvents) countBefore(p *Player) int {
+	return e.l.countBefore(p)
+}
+
+func (e skipListEvents) size() int {
+	return e.l.length
+}
+
+// GetPlayerRank 获取玩家排名（跳表跨度累加计算）
 func (l *LeaderboardSkipList) GetPlayerRank(playerID string) (RankInfo, bool) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
 	// 检查玩家是否存在于排行榜中
 	if node, exists := l.playerMap[playerID]; exists {
-		rank := 1
-		current := l.header.forward[0]
-		// 遍历跳表底层链表，统计排名
-		for current != nil && current != node {
-			rank++
-			current = current.forward[0]
-		}
-		return RankInfo{playerID, node.score, rank}, true // 返回排名信息
+		return l.rankInfo(node, l.getRank(node)), true // 返回排名信息
 	}
 	return RankInfo{}, false
 }
@@ -146,52 +421,203 @@ func (l *LeaderboardSkipList) GetTopN(n int) []RankInfo {
 		current = current.forward[0]
 		i++
 	}
-	return res
+	return applyRanking(l.opts.rankingMode, skipListCounter{l}, res)
 }
 
-// GetPlayerRankRange 获取周边排名（跳表底层遍历+排名计算）
+// GetPlayerRankRange 获取周边排名（跨度定位起始节点+底层遍历）
 func (l *LeaderboardSkipList) GetPlayerRankRange(playerID string, rangeN int) []RankInfo {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
 	// 检查玩家是否存在于排行榜中
 	if node, exists := l.playerMap[playerID]; exists {
-		// 计算当前排名
-		rank := 1
-		current := l.header.forward[0]
-		for current != nil && current != node {
-			rank++
-			current = current.forward[0]
-		}
+		rank := l.getRank(node) // 计算当前排名
 
 		start := max(1, rank-rangeN)                 // 计算排名范围的起始位置
 		end := min(l.getTotalPlayers(), rank+rangeN) // 计算排名范围的结束位置
 
-		var res []RankInfo
-		current = l.header.forward[0]
-		// 遍历跳表底层链表，获取排名范围内的玩家信息
-		for i := 0; current != nil && i < end; current = current.forward[0] {
-			if i+1 >= start {
-				res = append(res, RankInfo{
-					PlayerID: current.player.PlayerID,
-					Score:    current.score,
-					Rank:     i + 1,
-				})
-			}
-			i++
-		}
-		return res
+		return l.getRankRange(start, end)
 	}
 	return nil
 }
 
+// GetByRank 获取指定名次的玩家（跨度定位）
+// 名次从1开始，超出范围返回空的排名信息和 false
+func (l *LeaderboardSkipList) GetByRank(rank int) (RankInfo, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if node := l.getNodeByRank(rank); node != nil {
+		return l.rankInfo(node, rank), true
+	}
+	return RankInfo{}, false
+}
+
+// GetRankRange 获取名次区间 [start, end] 内的玩家（跨度定位起始节点+底层遍历）
+// 区间两端会被截断到 [1, 玩家总数]，区间为空时返回 nil
+func (l *LeaderboardSkipList) GetRankRange(start, end int) []RankInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.getRankRange(max(1, start), min(l.getTotalPlayers(), end))
+}
+
+// Len 获取排行榜玩家总数
+func (l *LeaderboardSkipList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.getTotalPlayers()
+}
+
+// 获取名次区间内的玩家（内部使用），调用方需保证 start、end 已截断到合法范围
+// 按排名直接定位起始节点，再沿底层链表获取排名范围内的玩家信息
+func (l *LeaderboardSkipList) getRankRange(start, end int) []RankInfo {
+	var res []RankInfo
+	current := l.getNodeByRank(start)
+	for i := start; current != nil && i <= end; current = current.forward[0] {
+		res = append(res, RankInfo{
+			PlayerID: current.player.PlayerID,
+			Score:    current.score,
+			Rank:     i,
+		})
+		i++
+	}
+	return applyRanking(l.opts.rankingMode, skipListCounter{l}, res)
+}
+
 // 获取总玩家数
 func (l *LeaderboardSkipList) getTotalPlayers() int {
-	count := 0
-	current := l.header.forward[0]
-	for current != nil {
-		count++
-		current = current.forward[0]
+	return l.length
+}
+
+// CountByScore 统计分数区间内的玩家数（跨度累加）
+// 区间内的玩家在跳表中连续排列，用区间之后的第一个位置减去区间之前的玩家数即可，时间复杂度 O(log n)
+func (l *LeaderboardSkipList) CountByScore(r ScoreRange) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	first, last := l.scoreRankRange(r)
+	return max(0, last-first+1)
+}
+
+// GetByScore 按名次顺序获取分数区间内的玩家（跨度定位起始节点+底层遍历）
+// 跳过区间内的前 offset 名玩家，最多返回 limit 名，limit 小于等于 0 表示不限制
+func (l *LeaderboardSkipList) GetByScore(r ScoreRange, offset, limit int) []RankInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	first, last := l.scoreRankRange(r)
+	first, last = pageRange(first, last, offset, limit)
+	if first > last {
+		return nil
 	}
-	return count
+	return l.getRankRange(first, last)
+}
+
+// 计算分数区间对应的序数名次区间 [first, last]（内部使用），区间为空时 first > last
+func (l *LeaderboardSkipList) scoreRankRange(r ScoreRange) (int, int) {
+	beforeRange, afterRange := l.opts.rangeBounds(r)
+	first := l.countWhile(beforeRange) + 1
+	last := l.countWhile(func(score int) bool { return !afterRange(score) })
+	return first, last
+}
+
+// GetPlayerPercentile 获取玩家击败的百分比（跨度累加计算排名），计算规则见 percentile
+func (l *LeaderboardSkipList) GetPlayerPercentile(playerID string) (float64, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if node, exists := l.playerMap[playerID]; exists {
+		info := l.rankInfo(node, l.getRank(node))
+		base := percentileBase(l.opts.rankingMode, l.length, l.scores.length)
+		return percentile(info.Rank, base), true
+	}
+	return 0, false
+}
+
+// GetTopPercent 获取名次位于前 p% 的玩家，计算规则见 topPercentCutoff
+// 排名策略下名次随序数名次单调不减，二分查找最后一名入选玩家，时间复杂度 O(log² n + k)
+func (l *LeaderboardSkipList) GetTopPercent(p float64) []RankInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	cutoff := topPercentCutoff(p, percentileBase(l.opts.rankingMode, l.length, l.scores.length))
+	if cutoff == 0 {
+		return nil
+	}
+	counter := newCachedCounter(skipListCounter{l})
+	last := sort.Search(l.length, func(i int) bool {
+		node := l.getNodeByRank(i + 1)
+		return l.opts.rankingMode.Rank(counter, i+1, node.score) > cutoff
+	})
+	return l.getRankRange(1, last)
+}
+
+// ForEach 按名次顺序遍历玩家（跳表底层遍历），fn 返回 false 时停止
+func (l *LeaderboardSkipList) ForEach(fn func(p Player) bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for current := l.header.forward[0]; current != nil; current = current.forward[0] {
+		if !fn(*current.player) {
+			return
+		}
+	}
+}
+
+// Load 用已排好序的玩家替换排行榜内容
+// 玩家已有序，无需查找插入位置，逐个追加到各层尾部并直接计算跨度，时间复杂度 O(n)
+func (l *LeaderboardSkipList) Load(players []Player) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.opts.checkSorted(players); err != nil {
+		return err
+	}
+	l.load(players)
+	return nil
+}
+
+// 用已校验的有序玩家重建跳表（内部使用），调用方需持有写锁
+func (l *LeaderboardSkipList) load(players []Player) {
+	l.reset()
+
+	tails := make([]*Node, MaxLevel)   // 各层当前的最后一个节点
+	tailRanks := make([]int, MaxLevel) // 各层最后一个节点的排名
+	for i := range tails {
+		tails[i] = l.header
+	}
+	var scores, counts []int // 不同分数及其玩家数，用于重建分数索引
+	for i := range players {
+		p := players[i]
+		level := randomLevel()
+		node := &Node{
+			player:    &p,
+			score:     p.Score,
+			timestamp: p.Timestamp,
+			forward:   make([]*Node, level),
+			span:      make([]int, level),
+		}
+		for lv := 0; lv < level; lv++ {
+			tails[lv].forward[lv] = node
+			tails[lv].span[lv] = i + 1 - tailRanks[lv]
+			tails[lv], tailRanks[lv] = node, i+1
+		}
+		l.level = max(l.level, level)
+		l.playerMap[p.PlayerID] = node
+
+		if n := len(scores); n > 0 && scores[n-1] == p.Score {
+			counts[n-1]++
+		} else {
+			scores = append(scores, p.Score)
+			counts = append(counts, 1)
+		}
+	}
+	l.length = len(players)
+	// 各层最后一个节点的跨度为其之后的节点数
+	for lv := 0; lv < l.level; lv++ {
+		tails[lv].span[lv] = l.length - tailRanks[lv]
+	}
+	l.scores.load(scores, counts)
 }
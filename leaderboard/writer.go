@@ -0,0 +1,51 @@
+package leaderboard
+
+import (
+	"context"
+	"time"
+)
+
+// 可能失败的写操作：持久化、复制的排行榜在写日志或达成共识失败时写操作不会生效，
+// LeaderboardService 的写方法无法返回错误，需要知道写操作是否生效的调用方使用本文件的函数
+
+// ContextWriter 写操作可能失败的排行榜，例如持久化和复制的排行榜
+// 返回错误时写操作未生效或结果未知，具体含义由实现说明
+type ContextWriter interface {
+	UpdateScoreContext(ctx context.Context, playerID string, score int, timestamp time.Time) (UpdateResult, error)
+	IncrementScoreContext(ctx context.Context, playerID string, delta int, timestamp time.Time) (RankInfo, error)
+	RemovePlayersContext(ctx context.Context, playerIDs ...string) (int, error)
+	ResetContext(ctx context.Context) error
+}
+
+// UpdateScoreContext 更新分数，board 实现 ContextWriter 时返回写操作失败的错误
+func UpdateScoreContext(ctx context.Context, board LeaderboardService, playerID string, score int, timestamp time.Time) (UpdateResult, error) {
+	if w, ok := board.(ContextWriter); ok {
+		return w.UpdateScoreContext(ctx, playerID, score, timestamp)
+	}
+	return board.UpdateScore(playerID, score, timestamp), nil
+}
+
+// IncrementScoreContext 增加分数，board 实现 ContextWriter 时返回写操作失败的错误
+func IncrementScoreContext(ctx context.Context, board LeaderboardService, playerID string, delta int, timestamp time.Time) (RankInfo, error) {
+	if w, ok := board.(ContextWriter); ok {
+		return w.IncrementScoreContext(ctx, playerID, delta, timestamp)
+	}
+	return board.IncrementScore(playerID, delta, timestamp), nil
+}
+
+// RemovePlayersContext 批量删除玩家，board 实现 ContextWriter 时返回写操作失败的错误
+func RemovePlayersContext(ctx context.Context, board LeaderboardService, playerIDs ...string) (int, error) {
+	if w, ok := board.(ContextWriter); ok {
+		return w.RemovePlayersContext(ctx, playerIDs...)
+	}
+	return board.RemovePlayers(playerIDs...), nil
+}
+
+// ResetContext 清空排行榜，board 实现 ContextWriter 时返回写操作失败的错误
+func ResetContext(ctx context.Context, board LeaderboardService) error {
+	if w, ok := board.(ContextWriter); ok {
+		return w.ResetContext(ctx)
+	}
+	board.Reset()
+	return nil
+}
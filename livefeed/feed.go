@@ -0,0 +1,243 @@
+// Package livefeed 通过 WebSocket 向观战客户端实时推送排行榜的名次变化
+//
+// 连接时的查询参数决定订阅内容，每个连接订阅一项：
+//
+//	?top=10              订阅前 10 名
+//	?player=p1&range=5   订阅玩家 p1 前后各 5 名
+//
+// 服务器推送的消息为 JSON 格式的 Message：
+//
+//	{"type":"snapshot","seq":1,"players":[...]}               连接后的第一条消息，包含完整列表
+//	{"type":"diff","seq":2,"changed":[...],"removed":["p3"]}  此后列表发生变化时推送差异
+//
+// changed 为新进入列表或分数、名次发生变化的玩家，removed 为离开列表的玩家ID；
+// 客户端删除 removed 中的玩家、用 changed 覆盖同ID的玩家后按名次排序，即得到最新列表。
+// seq 在每个连接内从 1 开始连续递增，客户端发现不连续时应重新连接
+package livefeed
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"game/internal/notify"
+	"game/leaderboard"
+)
+
+const (
+	MaxN           = 1000 // 订阅的最大名次数
+	MaxPlayerIDLen = 64   // 玩家ID的最大长度
+)
+
+// 消息类型
+const (
+	TypeSnapshot = "snapshot"
+	TypeDiff     = "diff"
+)
+
+// Message 推送给客户端的消息
+type Message struct {
+	Type    string                 `json:"type"`              // 消息类型：snapshot 或 diff
+	Seq     uint64                 `json:"seq"`               // 消息序号
+	Players []leaderboard.RankInfo `json:"players,omitempty"` // snapshot：完整列表，列表为空时省略
+	Changed []leaderboard.RankInfo `json:"changed,omitempty"` // diff：新进入列表或发生变化的玩家
+	Removed []string               `json:"removed,omitempty"` // diff：离开列表的玩家ID
+}
+
+// Feed 排行榜实时推送服务，实现 http.Handler
+//
+// 排行榜写入后调用 Notify 可以立即触发检查，未调用 Notify 的写入最迟在 pollInterval 后发现。
+// 每次推送后至少间隔 minInterval 才会再次检查，期间的多次写入合并为一次推送；
+// 客户端接收过慢导致单条消息在 writeTimeout 内无法写出时断开连接，不会为慢客户端积压消息
+type Feed struct {
+	board   leaderboard.LeaderboardService
+	changes *notify.Broadcast
+
+	pollInterval time.Duration // 检查不经过 Notify 的写入的间隔
+	minInterval  time.Duration // 同一连接两次推送的最小间隔
+	writeTimeout time.Duration // 单条消息的写超时
+	pingInterval time.Duration // 发送心跳的间隔，两个间隔内未收到响应视为连接断开
+	upgrader     websocket.Upgrader
+}
+
+// NewFeed 创建排行榜实时推送服务
+func NewFeed(board leaderboard.LeaderboardService) *Feed {
+	return &Feed{
+		board:        board,
+		changes:      notify.NewBroadcast(),
+		pollInterval: time.Second,
+		minInterval:  100 * time.Millisecond,
+		writeTimeout: 10 * time.Second,
+		pingInterval: 30 * time.Second,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 4096,
+			// 观战页面通常与服务不同源，由部署方通过网关控制访问
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// Notify 通知排行榜发生了写入，所有连接立即检查订阅的列表是否变化
+func (f *Feed) Notify() {
+	f.changes.Notify()
+}
+
+// subscription 一个连接订阅的列表
+type subscription struct {
+	top      int    // 前 N 名，为 0 时订阅玩家周边排名
+	playerID string // 玩家ID
+	rangeN   int    // 玩家前后各取的名次数
+}
+
+func parseSubscription(r *http.Request) (subscription, error) {
+	q := r.URL.Query()
+	if player := q.Get("player"); player != "" {
+		if len(player) > MaxPlayerIDLen {
+			return subscription{}, fmt.Errorf("player must be at most %d bytes", MaxPlayerIDLen)
+		}
+		rangeN, err := intParam(q.Get("range"), 5, 0)
+		if err != nil {
+			return subscription{}, fmt.Errorf("range %v", err)
+		}
+		return subscription{playerID: player, rangeN: rangeN}, nil
+	}
+	top, err := intParam(q.Get("top"), 10, 1)
+	if err != nil {
+		return subscription{}, fmt.Errorf("top %v", err)
+	}
+	return subscription{top: top}, nil
+}
+
+func intParam(raw string, def, lower int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < lower || v > MaxN {
+		return 0, fmt.Errorf("must be an integer between %d and %d", lower, MaxN)
+	}
+	return v, nil
+}
+
+// query 读取订阅的列表，订阅的玩家不在榜上时返回空列表
+func (s subscription) query(board leaderboard.LeaderboardService) []leaderboard.RankInfo {
+	if s.top > 0 {
+		return board.GetTopN(s.top)
+	}
+	return board.GetPlayerRankRange(s.playerID, s.rangeN)
+}
+
+// ServeHTTP 校验订阅参数后升级为 WebSocket 连接并开始推送
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sub, err := parseSubscription(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	conn, err := f.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade 已经回复了错误
+	}
+	defer conn.Close()
+
+	f.serve(conn, sub)
+}
+
+// serve 推送订阅列表的变化，直到连接断开或写入失败
+func (f *Feed) serve(conn *websocket.Conn, sub subscription) {
+	closed := make(chan struct{})
+	go f.readLoop(conn, closed)
+
+	poll := time.NewTicker(f.pollInterval)
+	defer poll.Stop()
+	ping := time.NewTicker(f.pingInterval)
+	defer ping.Stop()
+
+	var last []leaderboard.RankInfo
+	var seq uint64
+	for {
+		changed := f.changes.Wait()
+		cur := sub.query(f.board)
+
+		var msg *Message
+		if seq == 0 {
+			msg = &Message{Type: TypeSnapshot, Players: cur}
+		} else if updated, removed := diff(last, cur); len(updated) > 0 || len(removed) > 0 {
+			msg = &Message{Type: TypeDiff, Changed: updated, Removed: removed}
+		}
+		if msg != nil {
+			seq++
+			msg.Seq = seq
+			if err := f.write(conn, msg); err != nil {
+				return
+			}
+			last = cur
+
+			// 推送后等待 minInterval，期间的变化合并到下一次推送
+			select {
+			case <-time.After(f.minInterval):
+			case <-closed:
+				return
+			}
+		}
+
+		select {
+		case <-closed:
+			return
+		case <-changed:
+		case <-poll.C:
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(f.writeTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// write 在写超时内写出一条消息，超时说明客户端接收过慢，由调用方断开连接
+func (f *Feed) write(conn *websocket.Conn, msg *Message) error {
+	conn.SetWriteDeadline(time.Now().Add(f.writeTimeout))
+	return conn.WriteJSON(msg)
+}
+
+// readLoop 处理客户端发来的控制帧（心跳响应、关闭），忽略其他消息；连接断开或心跳超时后关闭 closed
+func (f *Feed) readLoop(conn *websocket.Conn, closed chan struct{}) {
+	defer close(closed)
+
+	conn.SetReadLimit(4096)
+	pongWait := 2 * f.pingInterval
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			return
+		}
+	}
+}
+
+// diff 比较前后两次的列表，返回新进入或发生变化的玩家，以及离开列表的玩家ID
+func diff(old, cur []leaderboard.RankInfo) (changed []leaderboard.RankInfo, removed []string) {
+	prev := make(map[string]leaderboard.RankInfo, len(old))
+	for _, info := range old {
+		prev[info.PlayerID] = info
+	}
+	for _, info := range cur {
+		if p, ok := prev[info.PlayerID]; !ok || p != info {
+			changed = append(changed, info)
+		}
+		delete(prev, info.PlayerID)
+	}
+	for _, info := range old {
+		if _, ok := prev[info.PlayerID]; ok {
+			removed = append(removed, info.PlayerID)
+		}
+	}
+	return changed, removed
+}
@@ -0,0 +1,179 @@
+package livefeed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"game/leaderboard"
+)
+
+// testClient 按协议应用推送的消息，维护客户端视角的列表
+type testClient struct {
+	t       *testing.T
+	conn    *websocket.Conn
+	seq     uint64
+	players map[string]leaderboard.RankInfo
+	diffs   int // 收到的 diff 消息数
+}
+
+func dial(t *testing.T, srv *httptest.Server, query string) *testClient {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testClient{t: t, conn: conn, players: make(map[string]leaderboard.RankInfo)}
+}
+
+// next 读取并应用一条消息
+func (c *testClient) next() {
+	c.t.Helper()
+	var msg Message
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := c.conn.ReadJSON(&msg); err != nil {
+		c.t.Fatal(err)
+	}
+	if msg.Seq != c.seq+1 {
+		c.t.Fatalf("seq %d after %d", msg.Seq, c.seq)
+	}
+	c.seq = msg.Seq
+	switch msg.Type {
+	case TypeSnapshot:
+		c.players = make(map[string]leaderboard.RankInfo)
+		for _, info := range msg.Players {
+			c.players[info.PlayerID] = info
+		}
+	case TypeDiff:
+		c.diffs++
+		if len(msg.Changed) == 0 && len(msg.Removed) == 0 {
+			c.t.Errorf("empty diff %+v", msg)
+		}
+		for _, id := range msg.Removed {
+			delete(c.players, id)
+		}
+		for _, info := range msg.Changed {
+			c.players[info.PlayerID] = info
+		}
+	default:
+		c.t.Fatalf("unexpected message %+v", msg)
+	}
+}
+
+func (c *testClient) list() []leaderboard.RankInfo {
+	res := []leaderboard.RankInfo{}
+	for _, info := range c.players {
+		res = append(res, info)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Rank < res[j].Rank })
+	return res
+}
+
+// waitFor 读取消息直到客户端视角的列表与 want 一致
+func (c *testClient) waitFor(want func() []leaderboard.RankInfo) {
+	c.t.Helper()
+	for {
+		c.next()
+		if w := want(); reflect.DeepEqual(c.list(), append([]leaderboard.RankInfo{}, w...)) {
+			return
+		}
+	}
+}
+
+func newTestFeed(t *testing.T) (*Feed, leaderboard.LeaderboardService, *httptest.Server) {
+	board := leaderboard.NewLeaderboardSkipList()
+	feed := NewFeed(board)
+	feed.minInterval = 50 * time.Millisecond
+	srv := httptest.NewServer(feed)
+	t.Cleanup(srv.Close)
+	return feed, board, srv
+}
+
+func TestTopNFeed(t *testing.T) {
+	feed, board, srv := newTestFeed(t)
+	base := time.Unix(0, 0)
+	for i, id := range []string{"A", "B", "C", "D"} {
+		board.UpdateScore(id, 100-i*10, base)
+	}
+
+	c := dial(t, srv, "top=3")
+	top3 := func() []leaderboard.RankInfo { return board.GetTopN(3) }
+	c.waitFor(top3)
+
+	// 前 3 名之外的变化不推送
+	board.UpdateScore("E", 10, base)
+	feed.Notify()
+	// D 超过 B 进入前 3 名，C 被挤出
+	board.UpdateScore("D", 95, base)
+	feed.Notify()
+	c.waitFor(top3)
+	if c.seq != 2 {
+		t.Errorf("got %d messages, want snapshot and one diff", c.seq)
+	}
+
+	// 一批连续写入合并为少量推送
+	for i := 0; i < 20; i++ {
+		board.IncrementScore("E", 10, base)
+		feed.Notify()
+	}
+	c.waitFor(top3)
+	if c.diffs > 4 {
+		t.Errorf("burst of 20 updates produced %d diffs", c.diffs)
+	}
+}
+
+func TestPlayerWindowFeed(t *testing.T) {
+	feed, board, srv := newTestFeed(t)
+	feed.pollInterval = 20 * time.Millisecond
+	base := time.Unix(0, 0)
+
+	// 订阅时玩家还不在榜上
+	c := dial(t, srv, "player=C&range=1")
+	window := func() []leaderboard.RankInfo { return board.GetPlayerRankRange("C", 1) }
+	c.next()
+	if len(c.players) != 0 {
+		t.Fatalf("got %v, want empty window", c.list())
+	}
+
+	// 未调用 Notify 的写入通过轮询发现
+	for i, id := range []string{"A", "B", "C", "D", "E"} {
+		board.UpdateScore(id, 100-i*10, base)
+	}
+	c.waitFor(window)
+
+	board.RemovePlayer("C")
+	c.waitFor(window)
+}
+
+func TestBadSubscription(t *testing.T) {
+	_, _, srv := newTestFeed(t)
+	for _, query := range []string{"top=0", "top=abc", "top=1001", "player=A&range=-1"} {
+		resp, err := http.Get(srv.URL + "/?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := []leaderboard.RankInfo{{PlayerID: "A", Score: 3, Rank: 1}, {PlayerID: "B", Score: 2, Rank: 2}, {PlayerID: "C", Score: 1, Rank: 3}}
+	cur := []leaderboard.RankInfo{{PlayerID: "A", Score: 3, Rank: 1}, {PlayerID: "D", Score: 2, Rank: 2}, {PlayerID: "B", Score: 2, Rank: 3}}
+	changed, removed := diff(old, cur)
+	if want := cur[1:]; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed: got %v, want %v", changed, want)
+	}
+	if want := []string{"C"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed: got %v, want %v", removed, want)
+	}
+}
@@ -1,74 +1,39 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"game/leaderboard"
-	"time"
+	"os"
+
+	"game/leaderboard/leaderboardtest"
 )
 
+// 对所有已注册的排行榜实现运行一致性测试：随机操作流同时在实现和参考模型上执行，逐个比较结果
 func main() {
-	// 初始化链表和跳表排行榜
-	linkedListLeaderboard := leaderboard.NewLeaderboardLinkedList()
-	skipListLeaderboard := leaderboard.NewLeaderboardSkipList()
-
-	// 定义测试数据
-	testData := []struct {
-		playerID  string
-		score     int
-		timestamp time.Time
-	}{
-		{"player1", 100, time.Now().Add(-time.Hour)},
-		{"player2", 90, time.Now()},
-		{"player3", 100, time.Now()},
-		{"player4", 80, time.Now()},
-	}
-
-	// 更新分数
-	for _, data := range testData {
-		linkedListLeaderboard.UpdateScore(data.playerID, data.score, data.timestamp)
-		skipListLeaderboard.UpdateScore(data.playerID, data.score, data.timestamp)
-	}
-
-	// 测试 GetPlayerRank
-	playerID := "player2"
-	linkedListRank, linkedListOk := linkedListLeaderboard.GetPlayerRank(playerID)
-	skipListRank, skipListOk := skipListLeaderboard.GetPlayerRank(playerID)
-	if linkedListOk != skipListOk || (linkedListOk && linkedListRank != skipListRank) {
-		fmt.Printf("GetPlayerRank 结果不一致: 链表 %+v, 跳表 %+v\n", linkedListRank, skipListRank)
-	} else {
-		fmt.Printf("GetPlayerRank 结果一致: %+v\n", linkedListRank)
-	}
-
-	// 测试 GetTopN
-	n := 2
-	linkedListTopN := linkedListLeaderboard.GetTopN(n)
-	skipListTopN := skipListLeaderboard.GetTopN(n)
-	if !equalRankInfoSlices(linkedListTopN, skipListTopN) {
-		fmt.Printf("GetTopN 结果不一致: 链表 %+v, 跳表 %+v\n", linkedListTopN, skipListTopN)
-	} else {
-		fmt.Printf("GetTopN 结果一致: %+v\n", linkedListTopN)
-	}
-
-	// 测试 GetPlayerRankRange
-	rangeN := 1
-	linkedListRankRange := linkedListLeaderboard.GetPlayerRankRange(playerID, rangeN)
-	skipListRankRange := skipListLeaderboard.GetPlayerRankRange(playerID, rangeN)
-	if !equalRankInfoSlices(linkedListRankRange, skipListRankRange) {
-		fmt.Printf("GetPlayerRankRange 结果不一致: 链表 %+v, 跳表 %+v\n", linkedListRankRange, skipListRankRange)
-	} else {
-		fmt.Printf("GetPlayerRankRange 结果一致: %+v\n", linkedListRankRange)
-	}
-}
-
-// equalRankInfoSlices 用于比较两个 RankInfo 切片是否相等
-func equalRankInfoSlices(a, b []leaderboard.RankInfo) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for i := range a {
-		if a[i] != b[i] {
-			return false
+	seed := flag.Int64("seed", 1, "第一条操作流的随机种子")
+	streams := flag.Int("streams", 2, "每组配置运行的操作流数")
+	ops := flag.Int("ops", 300, "每条操作流的长度")
+	players := flag.Int("players", 12, "操作流涉及的玩家数")
+	flag.Parse()
+
+	failed := false
+	for _, name := range leaderboardtest.Implementations() {
+		newBoard, _ := leaderboardtest.Lookup(name)
+		passed := 0
+	profiles:
+		for _, p := range leaderboardtest.Profiles() {
+			for i := 0; i < *streams; i++ {
+				if f := leaderboardtest.Verify(newBoard, p, *seed+int64(i), *ops, *players); f != nil {
+					fmt.Printf("%s 结果不一致: %v\n", name, f)
+					failed = true
+					break profiles
+				}
+				passed++
+			}
 		}
+		fmt.Printf("%s: %d 条操作流结果一致\n", name, passed)
+	}
+	if failed {
+		os.Exit(1)
 	}
-	return true
 }
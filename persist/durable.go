@@ -0,0 +1,190 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"game/leaderboard"
+)
+
+// DurableBoard 为任意 LeaderboardService 增加预写日志持久化
+// 每次写操作先追加到日志再应用到内存排行榜，读操作直接访问内存排行榜
+// 日志写入或刷盘（包括 SyncInterval 策略下的后台刷盘）失败后排行榜进入只读状态，后续写操作不再生效，可通过 Err 获取失败原因；
+// 需要知道每次写操作是否生效的调用方使用 leaderboard.ContextWriter 的方法，或检查 UpdateResult.Err
+type DurableBoard struct {
+	leaderboard.LeaderboardService
+
+	mu  sync.Mutex // 串行化写操作，保证日志顺序与应用顺序一致
+	log *Log
+	err error // 第一次日志写入失败的错误
+}
+
+// OpenDurable 打开 path 处的预写日志及其快照（path + ".snapshot"），恢复到 board 后返回持久化排行榜
+// 恢复时先装载快照，再重放日志中序列号大于快照序列号的记录；board 应为新创建的空排行榜，且配置与写入时一致
+func OpenDurable(path string, board leaderboard.LeaderboardService, opts LogOptions) (*DurableBoard, error) {
+	snapSeq, err := LoadSnapshot(SnapshotPath(path), board)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	log, err := OpenLog(path, opts, func(rec Record) error {
+		if rec.Seq <= snapSeq {
+			return nil // 已包含在快照中（保存快照后、截断日志前宕机）
+		}
+		if rec.Seq != snapSeq+1 {
+			return fmt.Errorf("persist: log record %d does not follow snapshot record %d", rec.Seq, snapSeq)
+		}
+		rec.Apply(board)
+		snapSeq = rec.Seq
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 日志中的记录都已包含在快照中时，从快照之后继续分配序列号
+	if log.NextSeq() <= snapSeq {
+		if err := log.Reset(snapSeq + 1); err != nil {
+			log.Close()
+			return nil, err
+		}
+	}
+	return &DurableBoard{LeaderboardService: board, log: log}, nil
+}
+
+// SnapshotPath 返回预写日志 path 对应的快照文件路径
+func SnapshotPath(path string) string {
+	return path + ".snapshot"
+}
+
+// Checkpoint 保存排行榜快照并截断已被快照覆盖的日志，之后的恢复只需装载快照再重放日志尾部
+// 保存快照期间写操作会被阻塞，读操作不受影响
+func (d *DurableBoard) Checkpoint() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.err != nil {
+		return d.err
+	}
+	seq := d.log.NextSeq() - 1
+	if err := SaveSnapshot(SnapshotPath(d.log.path), d.LeaderboardService, seq); err != nil {
+		return err
+	}
+	return d.log.Reset(seq + 1)
+}
+
+// UpdateScoreContext 记录日志后更新分数，日志写入失败时返回错误，写操作不生效
+func (d *DurableBoard) UpdateScoreContext(ctx context.Context, playerID string, score int, timestamp time.Time) (leaderboard.UpdateResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.append(ctx, Record{Op: OpUpdate, PlayerID: playerID, Score: score, Timestamp: timestamp}); err != nil {
+		return leaderboard.UpdateResult{}, err
+	}
+	return d.LeaderboardService.UpdateScore(playerID, score, timestamp), nil
+}
+
+// IncrementScoreContext 记录日志后增加分数，日志写入失败时返回错误，写操作不生效
+func (d *DurableBoard) IncrementScoreContext(ctx context.Context, playerID string, delta int, timestamp time.Time) (leaderboard.RankInfo, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.append(ctx, Record{Op: OpIncrement, PlayerID: playerID, Score: delta, Timestamp: timestamp}); err != nil {
+		return leaderboard.RankInfo{}, err
+	}
+	return d.LeaderboardService.IncrementScore(playerID, delta, timestamp), nil
+}
+
+// RemovePlayersContext 记录日志后批量删除玩家，日志写入失败时返回错误，写操作不生效
+func (d *DurableBoard) RemovePlayersContext(ctx context.Context, playerIDs ...string) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.append(ctx, Record{Op: OpRemove, PlayerIDs: playerIDs}); err != nil {
+		return 0, err
+	}
+	return d.LeaderboardService.RemovePlayers(playerIDs...), nil
+}
+
+// ResetContext 记录日志后清空排行榜，日志写入失败时返回错误，写操作不生效
+func (d *DurableBoard) ResetContext(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.append(ctx, Record{Op: OpReset}); err != nil {
+		return err
+	}
+	d.LeaderboardService.Reset()
+	return nil
+}
+
+// UpdateScore 记录日志后更新分数，日志写入失败时结果的 Err 不为空
+func (d *DurableBoard) UpdateScore(playerID string, score int, timestamp time.Time) leaderboard.UpdateResult {
+	res, err := d.UpdateScoreContext(context.Background(), playerID, score, timestamp)
+	if err != nil {
+		return leaderboard.UpdateResult{Err: err}
+	}
+	return res
+}
+
+// IncrementScore 记录日志后增加分数，日志写入失败时返回空
+func (d *DurableBoard) IncrementScore(playerID string, delta int, timestamp time.Time) leaderboard.RankInfo {
+	info, _ := d.IncrementScoreContext(context.Background(), playerID, delta, timestamp)
+	return info
+}
+
+// RemovePlayer 记录日志后删除玩家
+func (d *DurableBoard) RemovePlayer(playerID string) bool {
+	return d.RemovePlayers(playerID) == 1
+}
+
+// RemovePlayers 记录日志后批量删除玩家，日志写入失败时返回 0
+func (d *DurableBoard) RemovePlayers(playerIDs ...string) int {
+	removed, _ := d.RemovePlayersContext(context.Background(), playerIDs...)
+	return removed
+}
+
+// Reset 记录日志后清空排行榜
+func (d *DurableBoard) Reset() {
+	d.ResetContext(context.Background())
+}
+
+// Err 返回日志写入或刷盘失败的错误，正常时返回 nil
+func (d *DurableBoard) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.err != nil {
+		return d.err
+	}
+	return d.log.Err()
+}
+
+// Sync 立即将日志刷盘
+func (d *DurableBoard) Sync() error {
+	return d.log.Sync()
+}
+
+// Close 刷盘并关闭日志，关闭后写操作不再生效
+func (d *DurableBoard) Close() error {
+	return d.log.Close()
+}
+
+// append 追加日志记录，调用方需持有 d.mu
+// 日志写入失败后不再追加，之后的写操作都返回第一次失败的错误
+func (d *DurableBoard) append(ctx context.Context, rec Record) error {
+	if d.err != nil {
+		return d.err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := d.log.Append(rec); err != nil {
+		d.err = err
+		return err
+	}
+	return nil
+}
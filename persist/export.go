@@ -0,0 +1,253 @@
+package persist
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"game/leaderboard"
+)
+
+// 导出格式：每个玩家一条记录，按名次顺序排列
+//
+//	JSON Lines：{"rank":1,"playerId":"player1","score":100,"timestamp":"2024-01-01T00:00:00.123456789Z"}
+//	CSV：首行为表头 rank,playerId,score,timestamp，时间戳为 RFC 3339 格式（纳秒精度）
+//
+// 导入时 rank 列只作参考，名次由分数和时间戳重新计算；timestamp 为必填项，保证同分玩家的先后顺序在往返后保持不变
+
+// maxImportErrors 导入时最多收集的错误行数，超过后停止校验
+const maxImportErrors = 100
+
+var csvHeader = []string{"rank", "playerId", "score", "timestamp"}
+
+// exportRecord 导出文件中的一条记录，字段名与 leaderboard.RankInfo 的 JSON 标签一致
+type exportRecord struct {
+	Rank      int        `json:"rank"`
+	PlayerID  string     `json:"playerId"`
+	Score     *int       `json:"score"`
+	Timestamp *time.Time `json:"timestamp"`
+}
+
+// ImportError 导入数据中校验失败的一行
+type ImportError struct {
+	Line int   // 行号，从1开始
+	Err  error // 失败原因
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ImportErrors 导入数据中所有校验失败的行，最多收集 100 行
+type ImportErrors []*ImportError
+
+func (errs ImportErrors) Error() string {
+	var msgs []string
+	for i := 0; i < len(errs) && i < 5; i++ {
+		msgs = append(msgs, errs[i].Error())
+	}
+	if len(errs) > 5 {
+		msgs = append(msgs, fmt.Sprintf("and %d more errors", len(errs)-5))
+	}
+	return "persist: import failed: " + strings.Join(msgs, "; ")
+}
+
+// ExportJSONL 按名次顺序将排行榜以 JSON Lines 格式写入 w
+func ExportJSONL(w io.Writer, board leaderboard.LeaderboardService) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	var err error
+	rank := 0
+	board.ForEach(func(p leaderboard.Player) bool {
+		rank++
+		score, ts := p.Score, p.Timestamp.UTC()
+		err = enc.Encode(exportRecord{Rank: rank, PlayerID: p.PlayerID, Score: &score, Timestamp: &ts})
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ImportJSONL 读取 JSON Lines 格式的数据并替换排行榜内容，返回导入的玩家数
+// 任意一行校验失败时不修改排行榜，返回 ImportErrors；玩家数超过排行榜的上限时同样不修改排行榜，返回 leaderboard.ErrBoardFull
+func ImportJSONL(r io.Reader, board leaderboard.LeaderboardService) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordSize)
+	imp := newImporter()
+	line := 0
+	for scanner.Scan() && !imp.full() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var rec exportRecord
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rec); err != nil {
+			imp.fail(line, err)
+			continue
+		}
+		imp.add(line, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return imp.commit(board)
+}
+
+// ExportCSV 按名次顺序将排行榜以 CSV 格式写入 w
+func ExportCSV(w io.Writer, board leaderboard.LeaderboardService) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	var err error
+	rank := 0
+	board.ForEach(func(p leaderboard.Player) bool {
+		rank++
+		err = cw.Write([]string{
+			strconv.Itoa(rank),
+			p.PlayerID,
+			strconv.Itoa(p.Score),
+			p.Timestamp.UTC().Format(time.RFC3339Nano),
+		})
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportCSV 读取 CSV 格式的数据并替换排行榜内容，返回导入的玩家数
+// 首行必须为表头 rank,playerId,score,timestamp；任意一行校验失败时不修改排行榜，返回 ImportErrors；
+// 玩家数超过排行榜的上限时同样不修改排行榜，返回 leaderboard.ErrBoardFull
+func ImportCSV(r io.Reader, board leaderboard.LeaderboardService) (int, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return 0, ImportErrors{{Line: 1, Err: fmt.Errorf("read header: %w", err)}}
+	}
+	if strings.Join(header, ",") != strings.Join(csvHeader, ",") {
+		return 0, ImportErrors{{Line: 1, Err: fmt.Errorf("header must be %q", strings.Join(csvHeader, ","))}}
+	}
+
+	imp := newImporter()
+	for !imp.full() {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				imp.fail(parseErr.Line, parseErr.Err)
+				continue
+			}
+			return 0, err
+		}
+		line, _ := cr.FieldPos(0) // 读取出错时没有字段，FieldPos 会 panic，只能在成功读取后调用
+		rec, err := parseCSVRecord(fields)
+		if err != nil {
+			imp.fail(line, err)
+			continue
+		}
+		imp.add(line, rec)
+	}
+	return imp.commit(board)
+}
+
+// parseCSVRecord 解析一行 CSV 字段
+func parseCSVRecord(fields []string) (exportRecord, error) {
+	var rec exportRecord
+	var err error
+	if fields[0] != "" {
+		if rec.Rank, err = strconv.Atoi(fields[0]); err != nil {
+			return rec, fmt.Errorf("invalid rank %q", fields[0])
+		}
+	}
+	rec.PlayerID = fields[1]
+	if fields[2] != "" {
+		score, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return rec, fmt.Errorf("invalid score %q", fields[2])
+		}
+		rec.Score = &score
+	}
+	if fields[3] != "" {
+		ts, err := time.Parse(time.RFC3339Nano, fields[3])
+		if err != nil {
+			return rec, fmt.Errorf("invalid timestamp %q", fields[3])
+		}
+		rec.Timestamp = &ts
+	}
+	return rec, nil
+}
+
+// importer 收集并校验导入的记录
+type importer struct {
+	players []leaderboard.Player
+	lines   map[string]int // 玩家ID到首次出现行号的映射，用于检查重复
+	errs    ImportErrors
+}
+
+func newImporter() *importer {
+	return &importer{lines: make(map[string]int)}
+}
+
+func (imp *importer) full() bool {
+	return len(imp.errs) >= maxImportErrors
+}
+
+func (imp *importer) fail(line int, err error) {
+	imp.errs = append(imp.errs, &ImportError{Line: line, Err: err})
+}
+
+// add 校验一条记录，通过后加入待导入列表
+func (imp *importer) add(line int, rec exportRecord) {
+	switch {
+	case rec.PlayerID == "":
+		imp.fail(line, errors.New("missing playerId"))
+	case rec.Score == nil:
+		imp.fail(line, errors.New("missing score"))
+	case rec.Timestamp == nil:
+		imp.fail(line, errors.New("missing timestamp"))
+	case rec.Rank < 0:
+		imp.fail(line, fmt.Errorf("invalid rank %d", rec.Rank))
+	default:
+		if first, dup := imp.lines[rec.PlayerID]; dup {
+			imp.fail(line, fmt.Errorf("duplicate player %q (first seen on line %d)", rec.PlayerID, first))
+			return
+		}
+		imp.lines[rec.PlayerID] = line
+		imp.players = append(imp.players, leaderboard.Player{PlayerID: rec.PlayerID, Score: *rec.Score, Timestamp: *rec.Timestamp})
+	}
+}
+
+// commit 所有记录校验通过后替换排行榜内容
+func (imp *importer) commit(board leaderboard.LeaderboardService) (int, error) {
+	if len(imp.errs) > 0 {
+		return 0, imp.errs
+	}
+	if err := restore(board, imp.players); err != nil {
+		return 0, err
+	}
+	return len(imp.players), nil
+}
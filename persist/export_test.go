@@ -0,0 +1,99 @@
+package persist
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"game/leaderboard"
+)
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	formats := []struct {
+		name     string
+		export   func(*bytes.Buffer, leaderboard.LeaderboardService) error
+		importFn func(*bytes.Buffer, leaderboard.LeaderboardService) (int, error)
+	}{
+		{"JSONL",
+			func(b *bytes.Buffer, board leaderboard.LeaderboardService) error { return ExportJSONL(b, board) },
+			func(b *bytes.Buffer, board leaderboard.LeaderboardService) (int, error) { return ImportJSONL(b, board) }},
+		{"CSV",
+			func(b *bytes.Buffer, board leaderboard.LeaderboardService) error { return ExportCSV(b, board) },
+			func(b *bytes.Buffer, board leaderboard.LeaderboardService) (int, error) { return ImportCSV(b, board) }},
+	}
+
+	// 同分玩家只能靠时间戳区分先后，往返后顺序保持不变
+	src := leaderboard.NewLeaderboardSkipList()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 100; i++ {
+		src.UpdateScore(fmt.Sprintf("player%d", i), i%7, base.Add(time.Duration(100-i)*time.Nanosecond))
+	}
+	want := src.GetTopN(100)
+
+	for _, f := range formats {
+		var buf bytes.Buffer
+		if err := f.export(&buf, src); err != nil {
+			t.Fatalf("%s export error = %v", f.name, err)
+		}
+		dst := leaderboard.NewLeaderboardLinkedList()
+		dst.UpdateScore("stale", 1000, base)
+		n, err := f.importFn(&buf, dst)
+		if err != nil || n != 100 {
+			t.Fatalf("%s import = %d, %v; want 100", f.name, n, err)
+		}
+		if got := dst.GetTopN(100); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s round trip GetTopN() = %+v; want %+v", f.name, got, want)
+		}
+	}
+}
+
+func TestImport_ReportsLineErrors(t *testing.T) {
+	jsonl := strings.Join([]string{
+		`{"rank":1,"playerId":"a","score":10,"timestamp":"2024-01-01T00:00:00Z"}`,
+		`{"rank":2,"playerId":"b","score":9}`,
+		``,
+		`{"rank":3,"playerId":"a","score":8,"timestamp":"2024-01-01T00:00:00Z"}`,
+		`not json`,
+	}, "\n")
+	board := leaderboard.NewLeaderboardSkipList()
+	board.UpdateScore("keep", 1, time.Now())
+
+	_, err := ImportJSONL(strings.NewReader(jsonl), board)
+	var errs ImportErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("ImportJSONL() error = %v; want 3 line errors", err)
+	}
+	for i, line := range []int{2, 4, 5} {
+		if errs[i].Line != line {
+			t.Errorf("ImportJSONL() error %d line = %d; want %d", i, errs[i].Line, line)
+		}
+	}
+	if board.Len() != 1 {
+		t.Errorf("failed import changed the board, Len() = %d", board.Len())
+	}
+
+	csvData := "rank,playerId,score,timestamp\n" +
+		"1,a,10,2024-01-01T00:00:00Z\n" +
+		"2,b,ten,2024-01-01T00:00:00Z\n" +
+		"3,,8,2024-01-01T00:00:00Z\n" +
+		"4,d,7,yesterday\n"
+	_, err = ImportCSV(strings.NewReader(csvData), board)
+	if !errors.As(err, &errs) || len(errs) != 3 || errs[0].Line != 3 || errs[2].Line != 5 {
+		t.Fatalf("ImportCSV() error = %v; want errors on lines 3-5", err)
+	}
+	if _, err := ImportCSV(strings.NewReader("id,score\n"), board); err == nil {
+		t.Errorf("ImportCSV(bad header) should fail")
+	}
+
+	// 第一个字段的引号错误由 csv 包报告，同样带行号
+	_, err = ImportCSV(strings.NewReader("rank,playerId,score,timestamp\n"+`a"b,x,5,2024-01-01T00:00:00Z`+"\n"), board)
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Line != 2 {
+		t.Errorf("ImportCSV(bad quote) error = %v; want an error on line 2", err)
+	}
+	if board.Len() != 1 {
+		t.Errorf("failed import changed the board, Len() = %d", board.Len())
+	}
+}
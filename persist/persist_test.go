@@ -0,0 +1,346 @@
+package persist
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"game/leaderboard"
+	"game/leaderboard/leaderboardtest"
+)
+
+func TestDurableBoard_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "board.wal")
+	base := time.Unix(1700000000, 123456789)
+
+	d, err := OpenDurable(path, leaderboard.NewLeaderboardSkipList(), LogOptions{SyncPolicy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 10; i++ {
+		d.UpdateScore(fmt.Sprintf("player%d", i), i*10, base.Add(time.Duration(i)))
+	}
+	d.IncrementScore("player1", 200, base)
+	d.RemovePlayer("player2")
+	d.RemovePlayers("player3", "player4")
+	want := d.GetTopN(10)
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重放到另一种实现上得到相同的排行榜
+	d, err = OpenDurable(path, leaderboard.NewLeaderboardLinkedList(), LogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	if got := d.GetTopN(10); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("replayed GetTopN(10) = %+v; want %+v", got, want)
+	}
+	if got := d.log.NextSeq(); got != 14 {
+		t.Errorf("NextSeq() = %d; want 14", got)
+	}
+
+	d.Reset()
+	d.UpdateScore("player5", 1, base)
+	d.Close()
+	d, err = OpenDurable(path, leaderboard.NewLeaderboardSkipList(), LogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Len() != 1 {
+		t.Errorf("replayed Len() after Reset = %d; want 1", d.Len())
+	}
+	d.Close()
+}
+
+func TestLog_TruncatesCorruptTail(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(data []byte) []byte
+		records int // 截断后剩余的记录数
+	}{
+		{"torn header", func(data []byte) []byte { return append(data, 0x10, 0x00) }, 3},
+		{"torn payload", func(data []byte) []byte { return data[:len(data)-3] }, 2},
+		{"bad checksum", func(data []byte) []byte {
+			data[len(data)-1] ^= 0xff
+			return data
+		}, 2},
+		// 崩溃后文件长度已经落盘而数据没有，尾部读出来全是零
+		{"zero-filled tail", func(data []byte) []byte { return append(data, make([]byte, 100)...) }, 3},
+		{"zeroed last payload", func(data []byte) []byte {
+			payload := data[len(data)-5:]
+			for i := range payload {
+				payload[i] = 0
+			}
+			return append(data, make([]byte, 20)...)
+		}, 2},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "board.wal")
+		log, err := OpenLog(path, LogOptions{SyncPolicy: SyncNever}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			log.Append(Record{Op: OpUpdate, PlayerID: fmt.Sprintf("player%d", i), Score: i, Timestamp: time.Now()})
+		}
+		log.Close()
+
+		data, _ := os.ReadFile(path)
+		os.WriteFile(path, tt.corrupt(data), 0o644)
+
+		var records []Record
+		log, err = OpenLog(path, LogOptions{SyncPolicy: SyncNever}, func(rec Record) error {
+			records = append(records, rec)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: OpenLog() error = %v", tt.name, err)
+		}
+		wantRecords := tt.records
+		if len(records) != wantRecords {
+			t.Errorf("%s: replayed %d records; want %d", tt.name, len(records), wantRecords)
+		}
+
+		// 截断后继续追加的记录可以被正常重放
+		if seq, err := log.Append(Record{Op: OpReset}); err != nil || seq != uint64(wantRecords+1) {
+			t.Errorf("%s: Append() = %d, %v; want %d", tt.name, seq, err, wantRecords+1)
+		}
+		log.Close()
+		records = nil
+		log, _ = OpenLog(path, LogOptions{SyncPolicy: SyncNever}, func(rec Record) error {
+			records = append(records, rec)
+			return nil
+		})
+		if len(records) != wantRecords+1 || records[len(records)-1].Op != OpReset {
+			t.Errorf("%s: replayed %+v after append", tt.name, records)
+		}
+		log.Close()
+	}
+}
+
+func TestLog_RejectsCorruptionBeforeTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "board.wal")
+	log, err := OpenLog(path, LogOptions{SyncPolicy: SyncNever}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		log.Append(Record{Op: OpUpdate, PlayerID: fmt.Sprintf("player%d", i), Score: i, Timestamp: time.Now()})
+	}
+	log.Close()
+
+	// 破坏第一条记录的负载，之后的两条记录完好，不能被截断
+	data, _ := os.ReadFile(path)
+	data[frameHeaderSize+1] ^= 0xff
+	os.WriteFile(path, data, 0o644)
+
+	_, err = OpenLog(path, LogOptions{SyncPolicy: SyncNever}, nil)
+	if !errors.Is(err, ErrCorruptLog) || !strings.Contains(err.Error(), "offset 0") {
+		t.Errorf("OpenLog() error = %v; want ErrCorruptLog at offset 0", err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, data) {
+		t.Errorf("OpenLog() modified a corrupt log: %d bytes, want %d", len(after), len(data))
+	}
+}
+
+func TestDurableBoard_RejectsWritesAfterLogFailure(t *testing.T) {
+	d, err := OpenDurable(filepath.Join(t.TempDir(), "board.wal"), leaderboard.NewLeaderboardSkipList(), LogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.UpdateScore("player1", 10, time.Now())
+	d.Close()
+
+	res := d.UpdateScore("player1", 20, time.Now())
+	if d.Err() == nil || !errors.Is(res.Err, ErrClosed) || res.New.PlayerID != "" {
+		t.Errorf("UpdateScore() after Close = %+v, Err() = %v; want failure reported", res, d.Err())
+	}
+	if _, err := d.IncrementScoreContext(context.Background(), "player1", 1, time.Now()); !errors.Is(err, ErrClosed) {
+		t.Errorf("IncrementScoreContext() after Close error = %v; want ErrClosed", err)
+	}
+	if d.RemovePlayer("player1") || d.Len() != 1 {
+		t.Errorf("RemovePlayer() after Close should be rejected")
+	}
+	if info, _ := d.GetPlayerRank("player1"); info.Score != 10 {
+		t.Errorf("score after rejected writes = %d; want 10", info.Score)
+	}
+}
+
+func TestDurableBoard_RejectsWritesAfterSyncFailure(t *testing.T) {
+	d, err := OpenDurable(filepath.Join(t.TempDir(), "board.wal"), leaderboard.NewLeaderboardSkipList(),
+		LogOptions{SyncInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.UpdateScore("player1", 10, time.Now())
+
+	// 把日志文件换成管道：写入成功，但刷盘返回 EINVAL
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	d.log.mu.Lock()
+	file := d.log.file
+	d.log.file = w
+	d.log.mu.Unlock()
+	defer file.Close()
+	defer d.Close()
+
+	if res := d.UpdateScore("player1", 20, time.Now()); res.Err != nil {
+		t.Fatalf("UpdateScore() before sync failure = %+v", res)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for d.Err() == nil && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if d.Err() == nil {
+		t.Fatal("Err() = nil after background sync failure")
+	}
+	if res := d.UpdateScore("player1", 30, time.Now()); res.Err == nil {
+		t.Errorf("UpdateScore() after sync failure = %+v; want error", res)
+	}
+	if err := d.Sync(); err == nil {
+		t.Errorf("Sync() after sync failure should fail")
+	}
+	if info, _ := d.GetPlayerRank("player1"); info.Score != 20 {
+		t.Errorf("score after rejected write = %d; want 20", info.Score)
+	}
+}
+
+func TestDurableBoard_Checkpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "board.wal")
+	base := time.Unix(1700000000, 0)
+
+	d, err := OpenDurable(path, leaderboard.NewLeaderboardSkipList(), LogOptions{SyncPolicy: SyncNever})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		d.UpdateScore(fmt.Sprintf("player%d", i%300), i, base.Add(time.Duration(i)*time.Millisecond))
+	}
+	if err := d.Checkpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(path); info.Size() != 0 {
+		t.Errorf("log size after Checkpoint() = %d; want 0", info.Size())
+	}
+	d.IncrementScore("player1", 5000, base)
+	d.RemovePlayer("player2")
+	want := d.GetTopN(300)
+	d.Close()
+
+	// 快照 + 日志尾部恢复
+	d, err = OpenDurable(path, leaderboard.NewLeaderboardLinkedList(), LogOptions{SyncPolicy: SyncNever})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.GetTopN(300); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("recovered GetTopN() = %+v; want %+v", got, want)
+	}
+	if got := d.log.NextSeq(); got != 1003 {
+		t.Errorf("NextSeq() = %d; want 1003", got)
+	}
+
+	// 保存快照后、截断日志前宕机：日志中已被快照覆盖的记录会被跳过
+	if err := SaveSnapshot(SnapshotPath(path), d, d.log.NextSeq()-1); err != nil {
+		t.Fatal(err)
+	}
+	d.UpdateScore("late", 1, base)
+	d.Close()
+	d, err = OpenDurable(path, leaderboard.NewLeaderboardSkipList(), LogOptions{SyncPolicy: SyncNever})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Len() != len(want)+1 || d.log.NextSeq() != 1004 {
+		t.Errorf("recovered Len() = %d, NextSeq() = %d; want %d, 1004", d.Len(), d.log.NextSeq(), len(want)+1)
+	}
+	d.Close()
+}
+
+func TestSnapshot_DetectsCorruption(t *testing.T) {
+	board := leaderboard.NewLeaderboardSkipList()
+	for i := 0; i < 10; i++ {
+		board.UpdateScore(fmt.Sprintf("player%d", i), i, time.Now())
+	}
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, board, 42); err != nil {
+		t.Fatal(err)
+	}
+
+	// 按名次顺序写入的快照装载到降序和升序排行榜都能得到正确结果
+	restored := leaderboard.NewLeaderboardSkipList(leaderboard.WithOrder(leaderboard.Ascending))
+	seq, err := ReadSnapshot(bytes.NewReader(buf.Bytes()), restored)
+	if err != nil || seq != 42 {
+		t.Fatalf("ReadSnapshot() = %d, %v; want 42", seq, err)
+	}
+	if got, _ := restored.GetByRank(1); got.PlayerID != "player0" {
+		t.Errorf("ascending restored GetByRank(1) = %+v; want player0", got)
+	}
+
+	data := buf.Bytes()
+	data[len(data)/2] ^= 0xff
+	if _, err := ReadSnapshot(bytes.NewReader(data), leaderboard.NewLeaderboardSkipList()); !errors.Is(err, ErrBadSnapshot) {
+		t.Errorf("ReadSnapshot(corrupt) error = %v; want ErrBadSnapshot", err)
+	}
+	if _, err := ReadSnapshot(bytes.NewReader(data[:20]), leaderboard.NewLeaderboardSkipList()); !errors.Is(err, ErrBadSnapshot) {
+		t.Errorf("ReadSnapshot(truncated) error = %v; want ErrBadSnapshot", err)
+	}
+}
+
+func TestRestore_RejectsTooManyPlayers(t *testing.T) {
+	src := leaderboard.NewLeaderboardSkipList()
+	for i := 0; i < 10; i++ {
+		src.UpdateScore(fmt.Sprintf("player%d", i), i, time.Now())
+	}
+	var snapshot, jsonl bytes.Buffer
+	if err := WriteSnapshot(&snapshot, src, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportJSONL(&jsonl, src); err != nil {
+		t.Fatal(err)
+	}
+
+	// 降序排行榜走批量装载，升序排行榜排序规则不一致，改为逐个写入
+	for _, order := range []leaderboard.SortOrder{leaderboard.Descending, leaderboard.Ascending} {
+		board := leaderboard.NewLeaderboardSkipList(leaderboard.WithOrder(order), leaderboard.WithMaxPlayers(5))
+		board.UpdateScore("keep", 100, time.Now())
+
+		if _, err := ReadSnapshot(bytes.NewReader(snapshot.Bytes()), board); !errors.Is(err, leaderboard.ErrBoardFull) {
+			t.Errorf("order %v ReadSnapshot() error = %v; want ErrBoardFull", order, err)
+		}
+		if _, err := ImportJSONL(bytes.NewReader(jsonl.Bytes()), board); !errors.Is(err, leaderboard.ErrBoardFull) {
+			t.Errorf("order %v ImportJSONL() error = %v; want ErrBoardFull", order, err)
+		}
+		if got := board.GetTopN(10); len(got) != 1 || got[0].PlayerID != "keep" {
+			t.Errorf("order %v failed restore changed the board, GetTopN() = %+v", order, got)
+		}
+	}
+}
+
+func TestDurableBoard_Conformance(t *testing.T) {
+	dir := t.TempDir()
+	var boards []*DurableBoard
+	t.Cleanup(func() {
+		for _, d := range boards {
+			d.Close()
+		}
+	})
+	leaderboardtest.Test(t, func(opts ...leaderboard.Option) leaderboard.LeaderboardService {
+		path := filepath.Join(dir, fmt.Sprintf("board%d.wal", len(boards)))
+		d, err := OpenDurable(path, leaderboard.NewLeaderboardSkipList(opts...), LogOptions{SyncPolicy: SyncNever})
+		if err != nil {
+			panic(err) // 构造函数在子测试中调用，不能对外层测试调用 Fatal
+		}
+		boards = append(boards, d)
+		return d
+	}, leaderboardtest.Config{Streams: 1})
+}
@@ -0,0 +1,167 @@
+package persist
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"game/leaderboard"
+)
+
+// OpType 表示日志记录对应的排行榜写操作
+type OpType byte
+
+const (
+	OpUpdate    OpType = iota + 1 // UpdateScore
+	OpIncrement                   // IncrementScore
+	OpRemove                      // RemovePlayer / RemovePlayers
+	OpReset                       // Reset
+)
+
+// Record 一条写操作记录，按 Seq 严格递增写入日志
+// 日志记录的是操作本身而非操作结果，按相同顺序重放到相同配置的排行榜即可得到相同的状态
+type Record struct {
+	Seq       uint64    // 序列号，从1开始
+	Op        OpType    // 操作类型
+	PlayerID  string    // OpUpdate、OpIncrement 的玩家ID
+	Score     int       // OpUpdate 的分数或 OpIncrement 的增量
+	Timestamp time.Time // OpUpdate、OpIncrement 的得分时间戳
+	PlayerIDs []string  // OpRemove 删除的玩家ID
+}
+
+var errBadRecord = errors.New("persist: malformed record")
+
+// Apply 将记录对应的操作应用到排行榜
+func (r Record) Apply(board leaderboard.LeaderboardService) {
+	switch r.Op {
+	case OpUpdate:
+		board.UpdateScore(r.PlayerID, r.Score, r.Timestamp)
+	case OpIncrement:
+		board.IncrementScore(r.PlayerID, r.Score, r.Timestamp)
+	case OpRemove:
+		board.RemovePlayers(r.PlayerIDs...)
+	case OpReset:
+		board.Reset()
+	}
+}
+
+// marshal 将记录编码为紧凑的二进制格式
+// 格式：seq(uvarint) op(1B) 之后按操作类型写入玩家ID、分数(varint)、时间戳秒(varint)与纳秒(uvarint)或玩家ID列表
+func (r Record) marshal(buf []byte) []byte {
+	buf = binary.AppendUvarint(buf, r.Seq)
+	buf = append(buf, byte(r.Op))
+	switch r.Op {
+	case OpUpdate, OpIncrement:
+		buf = appendString(buf, r.PlayerID)
+		buf = binary.AppendVarint(buf, int64(r.Score))
+		buf = appendTime(buf, r.Timestamp)
+	case OpRemove:
+		buf = binary.AppendUvarint(buf, uint64(len(r.PlayerIDs)))
+		for _, id := range r.PlayerIDs {
+			buf = appendString(buf, id)
+		}
+	}
+	return buf
+}
+
+// unmarshalRecord 解码 marshal 生成的二进制记录
+func unmarshalRecord(data []byte) (Record, error) {
+	d := decoder{data: data}
+	r := Record{Seq: d.uvarint(), Op: OpType(d.byte())}
+	switch r.Op {
+	case OpUpdate, OpIncrement:
+		r.PlayerID = d.string()
+		r.Score = int(d.varint())
+		r.Timestamp = d.time()
+	case OpRemove:
+		n := d.uvarint()
+		if n > uint64(len(data)) {
+			return Record{}, errBadRecord
+		}
+		r.PlayerIDs = make([]string, 0, n)
+		for i := uint64(0); i < n; i++ {
+			r.PlayerIDs = append(r.PlayerIDs, d.string())
+		}
+	case OpReset:
+	default:
+		return Record{}, fmt.Errorf("%w: unknown op %d", errBadRecord, r.Op)
+	}
+	if d.err != nil || len(d.data) != 0 {
+		return Record{}, errBadRecord
+	}
+	return r, nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendTime(buf []byte, t time.Time) []byte {
+	buf = binary.AppendVarint(buf, t.Unix())
+	return binary.AppendUvarint(buf, uint64(t.Nanosecond()))
+}
+
+// decoder 顺序解码二进制数据，遇到第一个错误后后续读取均返回零值
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errBadRecord
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errBadRecord
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil || len(d.data) == 0 {
+		d.err = errBadRecord
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if d.err != nil || n > uint64(len(d.data)) {
+		d.err = errBadRecord
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+func (d *decoder) time() time.Time {
+	sec := d.varint()
+	nsec := d.uvarint()
+	if d.err != nil || nsec >= uint64(time.Second) {
+		d.err = errBadRecord
+		return time.Time{}
+	}
+	return time.Unix(sec, int64(nsec))
+}
@@ -0,0 +1,255 @@
+package persist
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"game/leaderboard"
+)
+
+// 快照文件格式（版本 1）：
+//
+//	magic "LBSNAP" | version(1B) | seq(uvarint)
+//	按名次顺序的玩家记录，每条为 1(1B) | playerID | score(varint) | 时间戳秒(varint) | 纳秒(uvarint)
+//	结束标记 0(1B) | 玩家数(uvarint) | 之前所有字节的 CRC32(4B, 小端)
+const (
+	snapshotMagic   = "LBSNAP"
+	snapshotVersion = 1
+)
+
+// ErrBadSnapshot 快照文件格式错误或校验失败
+var ErrBadSnapshot = errors.New("persist: bad snapshot")
+
+// WriteSnapshot 将排行榜按名次顺序写入 w，seq 为快照包含的最后一条日志记录的序列号
+func WriteSnapshot(w io.Writer, board leaderboard.LeaderboardService, seq uint64) error {
+	bw := bufio.NewWriter(w)
+	sum := crc32.New(crcTable)
+	out := io.MultiWriter(bw, sum)
+
+	buf := append([]byte(snapshotMagic), snapshotVersion)
+	buf = binary.AppendUvarint(buf, seq)
+	if _, err := out.Write(buf); err != nil {
+		return err
+	}
+
+	var count uint64
+	var err error
+	board.ForEach(func(p leaderboard.Player) bool {
+		buf = append(buf[:0], 1)
+		buf = appendString(buf, p.PlayerID)
+		buf = binary.AppendVarint(buf, int64(p.Score))
+		buf = appendTime(buf, p.Timestamp)
+		if _, err = out.Write(buf); err != nil {
+			return false
+		}
+		count++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	buf = append(buf[:0], 0)
+	buf = binary.AppendUvarint(buf, count)
+	if _, err := out.Write(buf); err != nil {
+		return err
+	}
+	if err := binary.Write(bw, binary.LittleEndian, sum.Sum32()); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ReadSnapshot 读取快照并用其内容替换排行榜，返回快照对应的日志序列号
+// 排行榜实现了 leaderboard.BulkLoader 且排序规则与快照一致时按名次顺序批量装载，否则逐个写入；
+// 快照的玩家数超过排行榜的玩家数上限时返回 leaderboard.ErrBoardFull，排行榜内容保持不变
+func ReadSnapshot(r io.Reader, board leaderboard.LeaderboardService) (uint64, error) {
+	cr := &crcReader{r: bufio.NewReader(r), sum: crc32.New(crcTable)}
+
+	magic := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(cr, magic); err != nil || string(magic[:len(snapshotMagic)]) != snapshotMagic {
+		return 0, fmt.Errorf("%w: missing header", ErrBadSnapshot)
+	}
+	if magic[len(snapshotMagic)] != snapshotVersion {
+		return 0, fmt.Errorf("%w: unsupported version %d", ErrBadSnapshot, magic[len(snapshotMagic)])
+	}
+	seq, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrBadSnapshot, err)
+	}
+
+	var players []leaderboard.Player
+	for {
+		marker, err := cr.ReadByte()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrBadSnapshot, err)
+		}
+		if marker == 0 {
+			break
+		}
+		p, err := readPlayer(cr)
+		if err != nil {
+			return 0, fmt.Errorf("%w: player %d: %v", ErrBadSnapshot, len(players)+1, err)
+		}
+		players = append(players, p)
+	}
+	count, err := binary.ReadUvarint(cr)
+	if err != nil || count != uint64(len(players)) {
+		return 0, fmt.Errorf("%w: player count mismatch", ErrBadSnapshot)
+	}
+	want := cr.sum.Sum32()
+	var got uint32
+	if err := binary.Read(cr.r, binary.LittleEndian, &got); err != nil || got != want {
+		return 0, fmt.Errorf("%w: checksum mismatch", ErrBadSnapshot)
+	}
+
+	if err := restore(board, players); err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// restore 用按名次排列的玩家替换排行榜内容
+// 玩家数超过排行榜的玩家数上限时返回 leaderboard.ErrBoardFull，排行榜内容保持不变
+func restore(board leaderboard.LeaderboardService, players []leaderboard.Player) error {
+	if loader, ok := board.(leaderboard.BulkLoader); ok {
+		// 只有排序规则与数据不一致时才改为逐个写入，其他错误（包括超过上限）直接返回
+		if err := loader.Load(players); !errors.Is(err, leaderboard.ErrUnsorted) {
+			return err
+		}
+	}
+	// 逐个写入时只有写到超出上限的玩家才知道排行榜已满，先保存原有内容，失败时写回
+	var saved []leaderboard.Player
+	board.ForEach(func(p leaderboard.Player) bool {
+		saved = append(saved, p)
+		return true
+	})
+	if err := replace(board, players); err != nil {
+		replace(board, saved)
+		return err
+	}
+	return nil
+}
+
+// replace 清空排行榜后逐个写入玩家，新玩家因排行榜已满未能上榜或写操作失败时返回错误
+func replace(board leaderboard.LeaderboardService, players []leaderboard.Player) error {
+	board.Reset()
+	for _, p := range players {
+		res := board.UpdateScore(p.PlayerID, p.Score, p.Timestamp)
+		if res.Err != nil {
+			return res.Err
+		}
+		if res.Rejected {
+			return fmt.Errorf("%w: %d players, rejected %q", leaderboard.ErrBoardFull, len(players), p.PlayerID)
+		}
+	}
+	return nil
+}
+
+func readPlayer(r *crcReader) (leaderboard.Player, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return leaderboard.Player{}, err
+	}
+	if n > maxRecordSize {
+		return leaderboard.Player{}, errBadRecord
+	}
+	id := make([]byte, n)
+	if _, err := io.ReadFull(r, id); err != nil {
+		return leaderboard.Player{}, err
+	}
+	score, err := binary.ReadVarint(r)
+	if err != nil {
+		return leaderboard.Player{}, err
+	}
+	sec, err := binary.ReadVarint(r)
+	if err != nil {
+		return leaderboard.Player{}, err
+	}
+	nsec, err := binary.ReadUvarint(r)
+	if err != nil {
+		return leaderboard.Player{}, err
+	}
+	if nsec >= uint64(time.Second) {
+		return leaderboard.Player{}, errBadRecord
+	}
+	return leaderboard.Player{PlayerID: string(id), Score: int(score), Timestamp: time.Unix(sec, int64(nsec))}, nil
+}
+
+// crcReader 读取数据的同时计算 CRC32 校验和
+type crcReader struct {
+	r   *bufio.Reader
+	sum hash.Hash32
+}
+
+func (c *crcReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.sum.Write(p[:n])
+	return n, err
+}
+
+func (c *crcReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.sum.Write([]byte{b})
+	}
+	return b, err
+}
+
+// SaveSnapshot 将排行榜快照原子地写入 path：先写临时文件并刷盘，再重命名覆盖
+func SaveSnapshot(path string, board leaderboard.LeaderboardService, seq uint64) error {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := WriteSnapshot(file, board, seq); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// LoadSnapshot 从 path 读取快照恢复排行榜，返回快照对应的日志序列号
+// 快照文件不存在时返回的错误满足 errors.Is(err, os.ErrNotExist)
+func LoadSnapshot(path string, board leaderboard.LeaderboardService) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return ReadSnapshot(file, board)
+}
+
+// syncDir 刷盘目录项，保证重命名在宕机后仍然生效
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
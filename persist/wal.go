@@ -0,0 +1,316 @@
+package persist
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// SyncPolicy 表示预写日志的刷盘策略
+type SyncPolicy int
+
+const (
+	SyncInterval SyncPolicy = iota // 后台按固定间隔刷盘（默认每秒一次，RPO 约 1 秒）
+	SyncAlways                     // 每条记录写入后立即刷盘，RPO 为 0
+	SyncNever                      // 不主动刷盘，由操作系统决定
+)
+
+const (
+	frameHeaderSize = 8       // 记录帧头：4 字节负载长度 + 4 字节 CRC32 校验和
+	maxRecordSize   = 1 << 24 // 单条记录负载的最大长度，超过视为损坏
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrClosed 日志已关闭
+var ErrClosed = errors.New("persist: log closed")
+
+// ErrCorruptLog 日志中间的记录损坏，之后还有数据，不能当作写了一半的尾部记录截断
+var ErrCorruptLog = errors.New("persist: corrupt log")
+
+// LogOptions 预写日志配置
+type LogOptions struct {
+	SyncPolicy   SyncPolicy    // 刷盘策略
+	SyncInterval time.Duration // SyncInterval 策略的刷盘间隔，默认 1 秒
+}
+
+// Log 仅追加写的预写日志
+// 每条记录以帧的形式写入：[负载长度 uint32][CRC32 uint32][负载]，打开时校验所有记录，
+// 文件末尾写了一半的记录会被截断，保证日志始终以完整记录结尾；其他位置的损坏不会被截断，打开时返回 ErrCorruptLog。
+// 刷盘失败后无法确定之前写入的记录是否落盘，日志不再接受写入，包括后台刷盘在内的第一次失败可以通过 Err 获取
+type Log struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	opts    LogOptions
+	nextSeq uint64 // 下一条记录的序列号
+	offset  int64  // 最后一条完整记录之后的文件偏移
+	dirty   bool   // 是否有未刷盘的写入
+	err     error  // 第一次刷盘失败的错误，之后的写入和刷盘都返回该错误
+	closed  bool
+	buf     []byte
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// OpenLog 打开或创建预写日志，并对已有记录逐条调用 fn 进行重放
+// 最后一条记录不完整或校验失败时（写入时宕机），从该记录处截断文件；
+// 损坏的记录之后还有数据时返回包含文件偏移的 ErrCorruptLog，不修改文件，避免丢弃已确认的记录；
+// fn 返回错误时停止打开并返回该错误
+func OpenLog(path string, opts LogOptions, fn func(Record) error) (*Log, error) {
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = time.Second
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Log{path: path, file: file, opts: opts, nextSeq: 1, done: make(chan struct{})}
+	good, err := l.replay(fn)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	// 截断损坏的尾部记录，并将写入位置移动到最后一条完整记录之后
+	if err := file.Truncate(good); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(good, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	l.offset = good
+
+	if opts.SyncPolicy == SyncInterval {
+		l.wg.Add(1)
+		go l.syncLoop()
+	}
+	return l, nil
+}
+
+// replay 从文件头开始读取并校验记录，返回最后一条完整记录之后的文件偏移
+// 只有延伸到文件末尾的损坏帧视为写了一半的尾部记录：帧头或负载不完整，或者帧之后直到文件末尾全是零字节时
+// 负载长度为 0 或校验和不符（文件长度已经增加而数据尚未落盘，未写入的部分读出来是零）；
+// 校验和正确但无法解码或序列号不连续的记录说明日志本身有误，同样返回错误
+func (l *Log) replay(fn func(Record) error) (int64, error) {
+	info, err := l.file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	fileSize := info.Size()
+	reader := bufio.NewReader(l.file)
+	var offset int64
+	header := make([]byte, frameHeaderSize)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return offset, nil // 文件结束或帧头不完整
+		}
+		size := binary.LittleEndian.Uint32(header[0:4])
+		sum := binary.LittleEndian.Uint32(header[4:8])
+		end := offset + frameHeaderSize + int64(size)
+		if end > fileSize {
+			return offset, nil // 负载不完整
+		}
+		if size > maxRecordSize {
+			return 0, fmt.Errorf("%w: record at offset %d has size %d", ErrCorruptLog, offset, size)
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return 0, err
+		}
+		// 记录编码后至少有一个字节，负载长度为 0 的帧只能来自零填充的尾部，空负载的校验和恰好也是 0
+		if size == 0 || crc32.Checksum(payload, crcTable) != sum {
+			torn, err := zeroTail(reader)
+			if err != nil {
+				return 0, err
+			}
+			if torn {
+				return offset, nil
+			}
+			if size == 0 {
+				return 0, fmt.Errorf("%w: empty record at offset %d, %d bytes follow", ErrCorruptLog, offset, fileSize-end)
+			}
+			return 0, fmt.Errorf("%w: record at offset %d: checksum mismatch, %d bytes follow", ErrCorruptLog, offset, fileSize-end)
+		}
+		// 序列号必须连续，第一条记录的序列号可以从任意值开始（快照之后截断的日志）
+		rec, err := unmarshalRecord(payload)
+		if err != nil {
+			return 0, fmt.Errorf("%w: record at offset %d: %v", ErrCorruptLog, offset, err)
+		}
+		if rec.Seq == 0 || (offset > 0 && rec.Seq != l.nextSeq) {
+			return 0, fmt.Errorf("%w: record at offset %d has seq %d, want %d", ErrCorruptLog, offset, rec.Seq, l.nextSeq)
+		}
+		if fn != nil {
+			if err := fn(rec); err != nil {
+				return 0, err
+			}
+		}
+		l.nextSeq = rec.Seq + 1
+		offset = end
+	}
+}
+
+// zeroTail 判断 r 剩余的内容是否全是零字节
+func zeroTail(r io.Reader) (bool, error) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		for _, b := range buf[:n] {
+			if b != 0 {
+				return false, nil
+			}
+		}
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// Append 为记录分配序列号并追加写入日志，返回分配的序列号
+// SyncAlways 策略下返回时记录已刷盘
+func (l *Log) Append(rec Record) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, ErrClosed
+	}
+	if l.err != nil {
+		return 0, l.err
+	}
+	rec.Seq = l.nextSeq
+	l.buf = append(l.buf[:0], make([]byte, frameHeaderSize)...)
+	l.buf = rec.marshal(l.buf)
+	payload := l.buf[frameHeaderSize:]
+	binary.LittleEndian.PutUint32(l.buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(l.buf[4:8], crc32.Checksum(payload, crcTable))
+
+	if _, err := l.file.Write(l.buf); err != nil {
+		l.rollback()
+		return 0, fmt.Errorf("persist: append record %d: %w", rec.Seq, err)
+	}
+	l.dirty = true
+	if l.opts.SyncPolicy == SyncAlways {
+		if err := l.syncLocked(); err != nil {
+			l.rollback()
+			return 0, err
+		}
+	}
+	l.nextSeq++
+	l.offset += int64(len(l.buf))
+	return rec.Seq, nil
+}
+
+// rollback 写入失败时丢弃最后一条完整记录之后的内容，避免残留半条记录
+func (l *Log) rollback() {
+	l.file.Truncate(l.offset)
+	l.file.Seek(l.offset, io.SeekStart)
+}
+
+// NextSeq 返回下一条记录将分配的序列号
+func (l *Log) NextSeq() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.nextSeq
+}
+
+// Reset 清空日志，之后写入的第一条记录序列号为 nextSeq
+// 用于保存快照后截断已被快照覆盖的记录
+func (l *Log) Reset(nextSeq uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+	if err := l.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := l.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	l.offset = 0
+	l.nextSeq = nextSeq
+	l.dirty = true
+	return l.syncLocked()
+}
+
+// Sync 将已写入的记录刷盘
+func (l *Log) Sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+	return l.syncLocked()
+}
+
+// Err 返回第一次刷盘失败的错误，正常时返回 nil
+func (l *Log) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.err
+}
+
+func (l *Log) syncLocked() error {
+	if l.err != nil {
+		return l.err
+	}
+	if !l.dirty {
+		return nil
+	}
+	if err := l.file.Sync(); err != nil {
+		l.err = fmt.Errorf("persist: sync log: %w", err)
+		return l.err
+	}
+	l.dirty = false
+	return nil
+}
+
+// syncLoop SyncInterval 策略下的后台刷盘协程，刷盘失败的错误记录在 l.err 中，由之后的写入返回
+func (l *Log) syncLoop() {
+	defer l.wg.Done()
+	ticker := time.NewTicker(l.opts.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.Sync()
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// Close 刷盘并关闭日志
+func (l *Log) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	err := l.syncLocked()
+	close(l.done)
+	l.mu.Unlock()
+
+	l.wg.Wait()
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
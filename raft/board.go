@@ -0,0 +1,178 @@
+package raft
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"game/leaderboard"
+	"game/persist"
+)
+
+// UpdateScoreContext 通过共识提交分数更新，返回领导者应用后的结果；ctx 没有截止时间时最多等待 RequestTimeout
+// 返回 ErrNoLeader、ErrLeadershipLost 时写操作一定未生效，返回 ErrUnknownOutcome 或 ctx 的错误时写操作可能已经生效
+func (n *Node) UpdateScoreContext(ctx context.Context, playerID string, score int, timestamp time.Time) (leaderboard.UpdateResult, error) {
+	ctx, cancel := n.requestTimeout(ctx)
+	defer cancel()
+	value, err := n.propose(ctx, persist.Record{Op: persist.OpUpdate, PlayerID: playerID, Score: score, Timestamp: timestamp})
+	if err != nil {
+		return leaderboard.UpdateResult{}, err
+	}
+	return value.(leaderboard.UpdateResult), nil
+}
+
+// IncrementScoreContext 通过共识提交分数增加，错误的含义同 UpdateScoreContext
+func (n *Node) IncrementScoreContext(ctx context.Context, playerID string, delta int, timestamp time.Time) (leaderboard.RankInfo, error) {
+	ctx, cancel := n.requestTimeout(ctx)
+	defer cancel()
+	value, err := n.propose(ctx, persist.Record{Op: persist.OpIncrement, PlayerID: playerID, Score: delta, Timestamp: timestamp})
+	if err != nil {
+		return leaderboard.RankInfo{}, err
+	}
+	return value.(leaderboard.RankInfo), nil
+}
+
+// RemovePlayersContext 通过共识提交批量删除，返回实际删除的数量，错误的含义同 UpdateScoreContext
+func (n *Node) RemovePlayersContext(ctx context.Context, playerIDs ...string) (int, error) {
+	ctx, cancel := n.requestTimeout(ctx)
+	defer cancel()
+	value, err := n.propose(ctx, persist.Record{Op: persist.OpRemove, PlayerIDs: append([]string(nil), playerIDs...)})
+	if err != nil {
+		return 0, err
+	}
+	return value.(int), nil
+}
+
+// ResetContext 通过共识提交清空排行榜，错误的含义同 UpdateScoreContext
+func (n *Node) ResetContext(ctx context.Context) error {
+	ctx, cancel := n.requestTimeout(ctx)
+	defer cancel()
+	_, err := n.propose(ctx, persist.Record{Op: persist.OpReset})
+	return err
+}
+
+// Linearizable 等待本地排行榜追上领导者的提交进度后返回，之后的查询能读到调用前已提交的全部写操作
+// ctx 没有截止时间时最多等待 RequestTimeout
+func (n *Node) Linearizable(ctx context.Context) (leaderboard.LeaderboardReader, error) {
+	ctx, cancel := n.requestTimeout(ctx)
+	defer cancel()
+	index, err := n.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.waitApplied(ctx, index); err != nil {
+		return nil, err
+	}
+	return n.board, nil
+}
+
+// Stale 返回本地排行榜，查询不与其他节点通信，可能读到旧数据；节点与集群断开后仍可查询
+func (n *Node) Stale() leaderboard.LeaderboardReader {
+	return n.board
+}
+
+// requestTimeout ctx 没有截止时间时加上 RequestTimeout 的超时
+func (n *Node) requestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, n.cfg.RequestTimeout)
+}
+
+// emptyBoard ReadLinearizable 模式下线性一致读失败时用于查询的空排行榜，不会被写入
+var emptyBoard leaderboard.LeaderboardReader = leaderboard.NewLeaderboardSkipList()
+
+// reader 按配置的读模式返回用于查询的排行榜，线性一致读失败时的行为见 ReadMode
+func (n *Node) reader() leaderboard.LeaderboardReader {
+	if n.cfg.ReadMode == ReadStale {
+		return n.board
+	}
+	r, err := n.Linearizable(context.Background())
+	if err == nil {
+		return r
+	}
+	if n.cfg.ReadMode == ReadLinearizableOrStale {
+		atomic.AddUint64(&n.staleReads, 1)
+		return n.board
+	}
+	atomic.AddUint64(&n.failedReads, 1)
+	return emptyBoard
+}
+
+// 以下方法实现 LeaderboardService，等待 RequestTimeout 后仍失败时的行为与 persist.DurableBoard 一致：
+// 写操作的失败原因通过 UpdateResult.Err 或对应的 Context 方法返回，查询失败时的行为见 ReadMode
+
+// UpdateScore 通过共识提交分数更新，失败时返回的 UpdateResult.Err 不为空
+func (n *Node) UpdateScore(playerID string, score int, timestamp time.Time) leaderboard.UpdateResult {
+	res, err := n.UpdateScoreContext(context.Background(), playerID, score, timestamp)
+	if err != nil {
+		return leaderboard.UpdateResult{Err: err}
+	}
+	return res
+}
+
+// IncrementScore 通过共识提交分数增加，失败时返回空
+func (n *Node) IncrementScore(playerID string, delta int, timestamp time.Time) leaderboard.RankInfo {
+	info, _ := n.IncrementScoreContext(context.Background(), playerID, delta, timestamp)
+	return info
+}
+
+// RemovePlayer 通过共识提交删除玩家
+func (n *Node) RemovePlayer(playerID string) bool {
+	return n.RemovePlayers(playerID) == 1
+}
+
+// RemovePlayers 通过共识提交批量删除玩家，失败时返回 0
+func (n *Node) RemovePlayers(playerIDs ...string) int {
+	removed, _ := n.RemovePlayersContext(context.Background(), playerIDs...)
+	return removed
+}
+
+// Reset 通过共识提交清空排行榜
+func (n *Node) Reset() {
+	n.ResetContext(context.Background())
+}
+
+func (n *Node) GetPlayerRank(playerID string) (leaderboard.RankInfo, bool) {
+	return n.reader().GetPlayerRank(playerID)
+}
+
+func (n *Node) GetTopN(k int) []leaderboard.RankInfo {
+	return n.reader().GetTopN(k)
+}
+
+func (n *Node) GetPlayerRankRange(playerID string, rangeN int) []leaderboard.RankInfo {
+	return n.reader().GetPlayerRankRange(playerID, rangeN)
+}
+
+func (n *Node) GetByRank(rank int) (leaderboard.RankInfo, bool) {
+	return n.reader().GetByRank(rank)
+}
+
+func (n *Node) GetRankRange(start, end int) []leaderboard.RankInfo {
+	return n.reader().GetRankRange(start, end)
+}
+
+func (n *Node) Len() int {
+	return n.reader().Len()
+}
+
+func (n *Node) CountByScore(r leaderboard.ScoreRange) int {
+	return n.reader().CountByScore(r)
+}
+
+func (n *Node) GetByScore(r leaderboard.ScoreRange, offset, limit int) []leaderboard.RankInfo {
+	return n.reader().GetByScore(r, offset, limit)
+}
+
+func (n *Node) GetPlayerPercentile(playerID string) (float64, bool) {
+	return n.reader().GetPlayerPercentile(playerID)
+}
+
+func (n *Node) GetTopPercent(p float64) []leaderboard.RankInfo {
+	return n.reader().GetTopPercent(p)
+}
+
+func (n *Node) ForEach(fn func(p leaderboard.Player) bool) {
+	n.reader().ForEach(fn)
+}
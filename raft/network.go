@@ -0,0 +1,86 @@
+package raft
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	errUnreachable = errors.New("raft: peer unreachable") // 请求未送达，可以安全重试
+	errReplyLost   = errors.New("raft: reply lost")       // 请求已送达但响应丢失，请求是否生效未知
+)
+
+// Network 进程内的模拟网络，节点之间的 RPC 直接调用对方的处理函数
+// Disconnect 可以模拟节点宕机或网络分区：断开的节点既收不到请求，也发不出请求
+type Network struct {
+	mu    sync.Mutex
+	nodes map[string]*Node
+	down  map[string]bool
+}
+
+// NewNetwork 创建模拟网络
+func NewNetwork() *Network {
+	return &Network{nodes: make(map[string]*Node), down: make(map[string]bool)}
+}
+
+// Disconnect 断开节点与网络的连接
+func (n *Network) Disconnect(id string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.down[id] = true
+}
+
+// Connect 恢复节点与网络的连接
+func (n *Network) Connect(id string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.down, id)
+}
+
+// register 注册节点，同一 ID 的节点重启后替换原来的节点
+func (n *Network) register(node *Node) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.nodes[node.id] = node
+}
+
+// unregister 注销节点，只在注册的仍是 node 时生效
+func (n *Network) unregister(node *Node) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.nodes[node.id] == node {
+		delete(n.nodes, node.id)
+	}
+}
+
+// peer 返回 from 可以访问的节点 to，任一方断开时返回 nil
+func (n *Network) peer(from, to string) *Node {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.down[from] || n.down[to] {
+		return nil
+	}
+	return n.nodes[to]
+}
+
+// call 从 from 向 to 发送请求并返回响应，在调用方的协程中同步执行对方的处理函数
+// 处理期间任一方断开时视为响应丢失
+func (n *Network) call(from, to string, req interface{}) (interface{}, error) {
+	node := n.peer(from, to)
+	if node == nil {
+		return nil, errUnreachable
+	}
+	resp := node.handle(req)
+	if resp == nil {
+		return nil, errUnreachable // 对方已停止
+	}
+	if n.peer(from, to) != node {
+		return nil, errReplyLost
+	}
+	return resp, nil
+}
@@ -0,0 +1,665 @@
+// Package raft 实现基于 Raft 共识的排行榜
+//
+// 集群中每个节点（Node）维护一份 Raft 日志和一个作为状态机的跳表排行榜。写操作转发给领导者，
+// 由领导者追加到日志并复制到多数节点后提交，各节点按日志顺序应用到本地排行榜，少数节点宕机不影响可用性，
+// 已提交的写操作也不会丢失。读操作支持线性一致读（通过 ReadIndex 向领导者确认提交进度后读取本地排行榜）
+// 和可能读到旧数据的本地读两种模式。
+//
+// 节点之间通过 Network 通信，目前只提供进程内的模拟网络，可以在同一进程中运行整个集群。
+// 日志完整保留在内存中，暂不支持快照压缩；节点需要持久化的状态保存在 Storage 中，重启时传入同一个 Storage 即可恢复
+package raft
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"game/internal/notify"
+	"game/leaderboard"
+	"game/persist"
+)
+
+var (
+	ErrStopped        = errors.New("raft: node stopped")                          // 节点已停止
+	ErrNoLeader       = errors.New("raft: no leader available")                   // 超时前没有找到可用的领导者，写操作未生效
+	ErrLeadershipLost = errors.New("raft: leadership lost before commit")         // 提交前领导者失去领导权，写操作未生效
+	ErrUnknownOutcome = errors.New("raft: outcome unknown, write may be applied") // 与领导者的连接中断，写操作可能已经生效
+)
+
+const maxAppendEntries = 512 // 单次 AppendEntries 携带的最大日志条数
+
+// Role 节点在集群中的角色
+type Role int
+
+const (
+	Follower Role = iota
+	Candidate
+	Leader
+)
+
+func (r Role) String() string {
+	switch r {
+	case Follower:
+		return "follower"
+	case Candidate:
+		return "candidate"
+	case Leader:
+		return "leader"
+	}
+	return "unknown"
+}
+
+// ReadMode 排行榜查询方法的读模式
+// LeaderboardService 的查询方法无法返回错误：ReadLinearizable 模式下等待 RequestTimeout 仍无法确认读进度时，
+// 查询按玩家不存在或结果为空返回，不会读到旧数据；需要区分查询失败和结果为空的调用方使用 Linearizable，失败时返回错误
+type ReadMode int
+
+const (
+	ReadLinearizable        ReadMode = iota // 线性一致读，能读到读请求开始前已提交的全部写操作，失败时计入 Status.FailedReads
+	ReadStale                               // 直接读取本地排行榜，可能读到旧数据，但不需要与领导者通信
+	ReadLinearizableOrStale                 // 优先线性一致读，失败时退回本地排行榜，可能读到旧数据，计入 Status.StaleReads
+)
+
+// Config 节点配置
+type Config struct {
+	ID                string               // 节点ID
+	Peers             []string             // 集群中全部节点的ID，可以包含自身
+	Network           *Network             // 节点之间通信的网络
+	Storage           *Storage             // 持久化状态，为 nil 时创建新的 Storage
+	Options           []leaderboard.Option // 状态机排行榜的配置，集群中所有节点必须一致
+	ElectionTimeout   time.Duration        // 选举超时的下限，实际超时在 [ElectionTimeout, 2*ElectionTimeout) 间随机，默认 150ms
+	HeartbeatInterval time.Duration        // 领导者发送心跳的间隔，默认 ElectionTimeout 的 1/3
+	RequestTimeout    time.Duration        // LeaderboardService 方法等待提交或确认读进度的超时，默认 2s
+	ReadMode          ReadMode             // LeaderboardService 查询方法的读模式，默认线性一致读，失败时查询返回空结果而不是旧数据
+}
+
+// Storage 节点需要持久化的状态：当前任期、投票对象和日志
+// 模拟网络下保存在内存中，节点停止后用同一个 Storage 创建新节点即模拟宕机重启
+type Storage struct {
+	term     uint64
+	votedFor string
+	log      []entry // log[0] 为占位，下标与日志序号一致
+}
+
+// NewStorage 创建空的持久化状态
+func NewStorage() *Storage {
+	return &Storage{log: make([]entry, 1)}
+}
+
+// entry 一条 Raft 日志，Record.Seq 为日志序号；领导者当选时追加的空操作日志 Op 为 0，应用时忽略
+type entry struct {
+	Term   uint64
+	Record persist.Record
+}
+
+// Status 节点状态
+type Status struct {
+	ID      string
+	Role    Role
+	Term    uint64
+	Leader  string // 已知的领导者，未知时为空
+	Commit  uint64 // 已提交的最后一条日志序号
+	Applied uint64 // 已应用到排行榜的最后一条日志序号
+
+	FailedReads uint64 // ReadLinearizable 模式下线性一致读失败的查询次数
+	StaleReads  uint64 // ReadLinearizableOrStale 模式下线性一致读失败后退回本地排行榜的查询次数
+}
+
+// waiter 等待日志应用结果的写操作
+type waiter struct {
+	term uint64
+	ch   chan result
+}
+
+type result struct {
+	value interface{}
+	err   error
+}
+
+// Node Raft 集群中的一个节点，实现 LeaderboardService
+type Node struct {
+	failedReads uint64 // 以下两个计数器原子访问，放在首位保证 64 位对齐，含义见 Status
+	staleReads  uint64
+
+	id    string
+	peers []string // 其他节点的ID
+	cfg   Config
+	net   *Network
+	board *leaderboard.LeaderboardSkipList // 状态机
+
+	mu         sync.Mutex
+	st         *Storage
+	role       Role
+	leader     string
+	commit     uint64
+	applied    uint64
+	nextIndex  map[string]uint64
+	matchIndex map[string]uint64
+	deadline   time.Time                // 选举超时的时间
+	triggers   map[string]chan struct{} // 有新日志时唤醒各节点的复制协程
+	waiters    map[uint64]waiter        // 按日志序号等待应用结果的写操作
+	stopped    bool
+
+	commits chan struct{}     // 提交进度前进时唤醒应用协程
+	applies *notify.Broadcast // 应用日志后唤醒等待读进度的查询
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewNode 创建节点并加入网络，调用 Start 后开始参与选举和复制
+func NewNode(cfg Config) *Node {
+	if cfg.ElectionTimeout <= 0 {
+		cfg.ElectionTimeout = 150 * time.Millisecond
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = cfg.ElectionTimeout / 3
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 2 * time.Second
+	}
+	if cfg.Storage == nil {
+		cfg.Storage = NewStorage()
+	}
+	n := &Node{
+		id:      cfg.ID,
+		cfg:     cfg,
+		net:     cfg.Network,
+		board:   leaderboard.NewLeaderboardSkipList(cfg.Options...),
+		st:      cfg.Storage,
+		waiters: make(map[uint64]waiter),
+		commits: make(chan struct{}, 1),
+		applies: notify.NewBroadcast(),
+		stop:    make(chan struct{}),
+	}
+	for _, p := range cfg.Peers {
+		if p != cfg.ID {
+			n.peers = append(n.peers, p)
+		}
+	}
+	n.net.register(n)
+	return n
+}
+
+// Start 启动选举计时和日志应用协程
+func (n *Node) Start() {
+	n.mu.Lock()
+	n.resetDeadline()
+	n.mu.Unlock()
+
+	n.wg.Add(2)
+	go n.tick()
+	go n.applyLoop()
+}
+
+// Stop 停止节点并退出网络，等待中的写操作返回 ErrStopped；本地排行榜保留最后的状态
+func (n *Node) Stop() {
+	n.mu.Lock()
+	if n.stopped {
+		n.mu.Unlock()
+		return
+	}
+	n.stopped = true
+	close(n.stop)
+	n.mu.Unlock()
+
+	n.net.unregister(n)
+	n.wg.Wait()
+}
+
+// Status 返回节点状态
+func (n *Node) Status() Status {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return Status{
+		ID:      n.id,
+		Role:    n.role,
+		Term:    n.st.term,
+		Leader:  n.leader,
+		Commit:  n.commit,
+		Applied: n.applied,
+
+		FailedReads: atomic.LoadUint64(&n.failedReads),
+		StaleReads:  atomic.LoadUint64(&n.staleReads),
+	}
+}
+
+func (n *Node) lastIndex() uint64 {
+	return uint64(len(n.st.log) - 1)
+}
+
+func (n *Node) quorum() int {
+	return (len(n.peers)+1)/2 + 1
+}
+
+// resetDeadline 重新随机选举超时，调用方需持有 n.mu
+func (n *Node) resetDeadline() {
+	timeout := n.cfg.ElectionTimeout + time.Duration(rand.Int63n(int64(n.cfg.ElectionTimeout)))
+	n.deadline = time.Now().Add(timeout)
+}
+
+// tick 定期检查选举超时，非领导者超时未收到心跳时发起选举
+func (n *Node) tick() {
+	defer n.wg.Done()
+
+	ticker := time.NewTicker(n.cfg.ElectionTimeout / 10)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-ticker.C:
+		}
+		n.mu.Lock()
+		if !n.stopped && n.role != Leader && time.Now().After(n.deadline) {
+			n.startElection()
+		}
+		n.mu.Unlock()
+	}
+}
+
+// startElection 进入下一任期并向其他节点请求投票，调用方需持有 n.mu
+func (n *Node) startElection() {
+	n.st.term++
+	n.st.votedFor = n.id
+	n.role, n.leader = Candidate, ""
+	n.resetDeadline()
+
+	term := n.st.term
+	req := &voteRequest{Term: term, Candidate: n.id, LastIndex: n.lastIndex(), LastTerm: n.st.log[n.lastIndex()].Term}
+	votes := 1
+	if votes >= n.quorum() {
+		n.becomeLeader()
+		return
+	}
+	for _, peer := range n.peers {
+		n.wg.Add(1)
+		go func(peer string) {
+			defer n.wg.Done()
+
+			resp, err := n.net.call(n.id, peer, req)
+			if err != nil {
+				return
+			}
+			reply := resp.(*voteReply)
+
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			if reply.Term > n.st.term {
+				n.becomeFollower(reply.Term)
+				return
+			}
+			if !reply.Granted || n.role != Candidate || n.st.term != term || n.stopped {
+				return
+			}
+			votes++
+			if votes == n.quorum() {
+				n.becomeLeader()
+			}
+		}(peer)
+	}
+}
+
+// becomeFollower 转为跟随者，term 大于当前任期时进入新任期，调用方需持有 n.mu
+func (n *Node) becomeFollower(term uint64) {
+	if term > n.st.term {
+		n.st.term, n.st.votedFor = term, ""
+		n.leader = ""
+	}
+	if n.role != Follower {
+		n.role = Follower
+		n.resetDeadline()
+	}
+}
+
+// becomeLeader 成为领导者，追加一条当前任期的空操作日志并为每个节点启动复制协程，调用方需持有 n.mu
+// 空操作日志提交后，之前任期的日志随之提交，领导者才能确定提交进度，为线性一致读提供依据
+func (n *Node) becomeLeader() {
+	n.role, n.leader = Leader, n.id
+	term := n.st.term
+	n.st.log = append(n.st.log, entry{Term: term, Record: persist.Record{Seq: n.lastIndex() + 1}})
+
+	n.nextIndex = make(map[string]uint64, len(n.peers))
+	n.matchIndex = make(map[string]uint64, len(n.peers))
+	n.triggers = make(map[string]chan struct{}, len(n.peers))
+	for _, peer := range n.peers {
+		n.nextIndex[peer] = n.lastIndex()
+		trigger := make(chan struct{}, 1)
+		n.triggers[peer] = trigger
+		n.wg.Add(1)
+		go n.replicate(peer, term, trigger)
+	}
+	n.advanceCommit()
+}
+
+// replicate 领导者向 peer 复制日志的协程，有新日志时立即发送，否则按心跳间隔发送，失去该任期的领导权后退出
+func (n *Node) replicate(peer string, term uint64, trigger chan struct{}) {
+	defer n.wg.Done()
+
+	for {
+		n.mu.Lock()
+		if n.stopped || n.role != Leader || n.st.term != term {
+			n.mu.Unlock()
+			return
+		}
+		next := n.nextIndex[peer]
+		end := n.lastIndex() + 1
+		if end-next > maxAppendEntries {
+			end = next + maxAppendEntries
+		}
+		req := &appendRequest{
+			Term:      term,
+			Leader:    n.id,
+			PrevIndex: next - 1,
+			PrevTerm:  n.st.log[next-1].Term,
+			Entries:   append([]entry(nil), n.st.log[next:end]...),
+			Commit:    n.commit,
+		}
+		n.mu.Unlock()
+
+		if resp, err := n.net.call(n.id, peer, req); err == nil && n.handleAppendReply(peer, term, req, resp.(*appendReply)) {
+			continue
+		}
+		select {
+		case <-n.stop:
+			return
+		case <-trigger:
+		case <-time.After(n.cfg.HeartbeatInterval):
+		}
+	}
+}
+
+// handleAppendReply 处理 AppendEntries 的响应，返回是否还有需要立即发送的日志
+func (n *Node) handleAppendReply(peer string, term uint64, req *appendRequest, reply *appendReply) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if reply.Term > n.st.term {
+		n.becomeFollower(reply.Term)
+		return false
+	}
+	if n.role != Leader || n.st.term != term {
+		return false
+	}
+	if !reply.Success {
+		// 跟随者日志较短或在 PrevIndex 处冲突，回退到其报告的位置重试
+		next := reply.LastIndex + 1
+		if next >= req.PrevIndex+1 {
+			next = req.PrevIndex
+		}
+		if next < 1 {
+			next = 1
+		}
+		n.nextIndex[peer] = next
+		return true
+	}
+	match := req.PrevIndex + uint64(len(req.Entries))
+	if match > n.matchIndex[peer] {
+		n.matchIndex[peer] = match
+	}
+	n.nextIndex[peer] = match + 1
+	n.advanceCommit()
+	return match < n.lastIndex()
+}
+
+// advanceCommit 领导者将提交进度推进到多数节点已复制的、属于当前任期的最后一条日志，调用方需持有 n.mu
+func (n *Node) advanceCommit() {
+	for i := n.lastIndex(); i > n.commit; i-- {
+		if n.st.log[i].Term != n.st.term {
+			break // 之前任期的日志只能随当前任期的日志一起提交
+		}
+		count := 1
+		for _, peer := range n.peers {
+			if n.matchIndex[peer] >= i {
+				count++
+			}
+		}
+		if count >= n.quorum() {
+			n.setCommit(i)
+			return
+		}
+	}
+}
+
+// setCommit 推进提交进度并唤醒应用协程，调用方需持有 n.mu
+func (n *Node) setCommit(index uint64) {
+	if index <= n.commit {
+		return
+	}
+	n.commit = index
+	select {
+	case n.commits <- struct{}{}:
+	default:
+	}
+}
+
+// applyLoop 按日志顺序将已提交的日志应用到排行榜，并把结果交给等待的写操作
+func (n *Node) applyLoop() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-n.stop:
+			n.mu.Lock()
+			for index, w := range n.waiters {
+				w.ch <- result{err: ErrStopped}
+				delete(n.waiters, index)
+			}
+			n.mu.Unlock()
+			return
+		case <-n.commits:
+		}
+		for {
+			n.mu.Lock()
+			if n.applied >= n.commit {
+				n.mu.Unlock()
+				break
+			}
+			index := n.applied + 1
+			e := n.st.log[index]
+			n.mu.Unlock()
+
+			value := apply(n.board, e.Record)
+
+			n.mu.Lock()
+			n.applied = index
+			w, ok := n.waiters[index]
+			delete(n.waiters, index)
+			n.mu.Unlock()
+			if ok {
+				if w.term == e.Term {
+					w.ch <- result{value: value}
+				} else {
+					w.ch <- result{err: ErrLeadershipLost} // 该位置已被新领导者的日志覆盖
+				}
+			}
+		}
+		n.applies.Notify()
+	}
+}
+
+// apply 将日志记录应用到排行榜并返回对应写操作的结果
+func apply(board leaderboard.LeaderboardService, rec persist.Record) interface{} {
+	switch rec.Op {
+	case persist.OpUpdate:
+		return board.UpdateScore(rec.PlayerID, rec.Score, rec.Timestamp)
+	case persist.OpIncrement:
+		return board.IncrementScore(rec.PlayerID, rec.Score, rec.Timestamp)
+	case persist.OpRemove:
+		return board.RemovePlayers(rec.PlayerIDs...)
+	case persist.OpReset:
+		board.Reset()
+	}
+	return nil
+}
+
+// propose 提交写操作并返回应用结果；非领导者将写操作转发给领导者，领导者未知时等待选举完成
+func (n *Node) propose(ctx context.Context, rec persist.Record) (interface{}, error) {
+	for {
+		value, err := n.proposeLocal(ctx, rec)
+		if err != ErrNoLeader {
+			return value, err
+		}
+		if leader := n.Status().Leader; leader != "" && leader != n.id {
+			deadline, _ := ctx.Deadline()
+			resp, err := n.net.call(n.id, leader, &proposeRequest{Record: rec, Deadline: deadline})
+			if err == errReplyLost {
+				return nil, ErrUnknownOutcome
+			}
+			if err == nil {
+				reply := resp.(*proposeReply)
+				switch reply.Err {
+				case ErrNoLeader:
+					// 对方已不是领导者，写操作未生效，得知新的领导者后重试
+				case ErrStopped, context.DeadlineExceeded, context.Canceled:
+					return nil, ErrUnknownOutcome
+				default:
+					return reply.Value, reply.Err
+				}
+			}
+		}
+		if err := n.backoff(ctx); err != nil {
+			return nil, ErrNoLeader
+		}
+	}
+}
+
+// proposeLocal 领导者追加日志并等待提交后的应用结果，不是领导者时返回 ErrNoLeader
+func (n *Node) proposeLocal(ctx context.Context, rec persist.Record) (interface{}, error) {
+	n.mu.Lock()
+	if n.stopped {
+		n.mu.Unlock()
+		return nil, ErrStopped
+	}
+	if n.role != Leader {
+		n.mu.Unlock()
+		return nil, ErrNoLeader
+	}
+	term := n.st.term
+	rec.Seq = n.lastIndex() + 1
+	n.st.log = append(n.st.log, entry{Term: term, Record: rec})
+	w := waiter{term: term, ch: make(chan result, 1)}
+	n.waiters[rec.Seq] = w
+	for _, trigger := range n.triggers {
+		select {
+		case trigger <- struct{}{}:
+		default:
+		}
+	}
+	n.advanceCommit() // 单节点集群追加即提交
+	n.mu.Unlock()
+
+	select {
+	case r := <-w.ch:
+		return r.value, r.err
+	case <-ctx.Done():
+		n.mu.Lock()
+		delete(n.waiters, rec.Seq)
+		n.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// backoff 等待一个心跳间隔后重试，期间节点停止或 ctx 结束时返回错误
+func (n *Node) backoff(ctx context.Context) error {
+	select {
+	case <-time.After(n.cfg.HeartbeatInterval):
+		return nil
+	case <-n.stop:
+		return ErrStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// readIndex 返回线性一致读需要等待应用到的日志序号，非领导者向领导者查询
+func (n *Node) readIndex(ctx context.Context) (uint64, error) {
+	for {
+		index, err := n.readIndexLocal(ctx)
+		if err != ErrNoLeader {
+			return index, err
+		}
+		if leader := n.Status().Leader; leader != "" && leader != n.id {
+			deadline, _ := ctx.Deadline()
+			if resp, err := n.net.call(n.id, leader, &readIndexRequest{Deadline: deadline}); err == nil {
+				if reply := resp.(*readIndexReply); reply.Err == nil {
+					return reply.Index, nil
+				}
+			}
+		}
+		if err := n.backoff(ctx); err != nil {
+			return 0, ErrNoLeader
+		}
+	}
+}
+
+// readIndexLocal 领导者确认自己仍被多数节点承认后返回当前的提交进度
+// 不是领导者、当前任期的空操作日志尚未提交或无法确认领导权时返回 ErrNoLeader
+func (n *Node) readIndexLocal(ctx context.Context) (uint64, error) {
+	n.mu.Lock()
+	if n.stopped {
+		n.mu.Unlock()
+		return 0, ErrStopped
+	}
+	term, index := n.st.term, n.commit
+	ready := n.role == Leader && n.st.log[index].Term == term
+	n.mu.Unlock()
+
+	if !ready || !n.confirmLeadership(ctx, term) {
+		return 0, ErrNoLeader
+	}
+	return index, nil
+}
+
+// confirmLeadership 向其他节点发送心跳，多数节点在任期 term 内响应时返回 true
+func (n *Node) confirmLeadership(ctx context.Context, term uint64) bool {
+	if n.quorum() == 1 {
+		return true
+	}
+	acks := make(chan bool, len(n.peers))
+	req := &appendRequest{Term: term, Leader: n.id}
+	for _, peer := range n.peers {
+		go func(peer string) {
+			resp, err := n.net.call(n.id, peer, req)
+			acks <- err == nil && resp.(*appendReply).Term == term
+		}(peer)
+	}
+	granted := 1
+	for range n.peers {
+		select {
+		case ok := <-acks:
+			if ok {
+				granted++
+			}
+			if granted >= n.quorum() {
+				return true
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return false
+}
+
+// waitApplied 等待本地排行榜应用到日志序号 index
+func (n *Node) waitApplied(ctx context.Context, index uint64) error {
+	for {
+		applies := n.applies.Wait()
+		n.mu.Lock()
+		applied := n.applied
+		n.mu.Unlock()
+		if applied >= index {
+			return nil
+		}
+		select {
+		case <-applies:
+		case <-n.stop:
+			return ErrStopped
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package raft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"game/leaderboard"
+	"game/leaderboard/leaderboardtest"
+)
+
+type testCluster struct {
+	t        *testing.T
+	net      *Network
+	ids      []string
+	storages map[string]*Storage
+	nodes    map[string]*Node
+}
+
+// newTestCluster 启动 size 个节点的集群，缩短选举超时以加快测试
+func newTestCluster(t *testing.T, size int) *testCluster {
+	c := &testCluster{t: t, net: NewNetwork(), storages: make(map[string]*Storage), nodes: make(map[string]*Node)}
+	for i := 0; i < size; i++ {
+		c.ids = append(c.ids, fmt.Sprintf("n%d", i))
+	}
+	for _, id := range c.ids {
+		c.storages[id] = NewStorage()
+		c.start(id)
+	}
+	t.Cleanup(func() {
+		for _, n := range c.nodes {
+			n.Stop()
+		}
+	})
+	return c
+}
+
+// start 用节点原有的 Storage 启动节点，节点已在运行时先停止，模拟宕机重启
+func (c *testCluster) start(id string) *Node {
+	if n := c.nodes[id]; n != nil {
+		n.Stop()
+	}
+	n := NewNode(Config{
+		ID:              id,
+		Peers:           c.ids,
+		Network:         c.net,
+		Storage:         c.storages[id],
+		ElectionTimeout: 50 * time.Millisecond,
+	})
+	n.Start()
+	c.nodes[id] = n
+	return n
+}
+
+// leader 等待除 except 之外的节点中选出领导者
+func (c *testCluster) leader(except ...string) *Node {
+	c.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+	search:
+		for _, id := range c.ids {
+			for _, e := range except {
+				if id == e {
+					continue search
+				}
+			}
+			if n := c.nodes[id]; n.Status().Role == Leader {
+				return n
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	c.t.Fatal("no leader elected")
+	return nil
+}
+
+func (c *testCluster) follower(leader *Node) *Node {
+	for _, id := range c.ids {
+		if id != leader.id {
+			return c.nodes[id]
+		}
+	}
+	return nil
+}
+
+func TestCluster_ReplicatesWrites(t *testing.T) {
+	c := newTestCluster(t, 3)
+	leader := c.leader()
+	follower := c.follower(leader)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 写操作可以发往任意节点，跟随者转发给领导者，返回领导者应用后的结果
+	if res := follower.UpdateScore("a", 10, base); !res.ScoreChanged || res.New.Rank != 1 {
+		t.Errorf("follower UpdateScore(a) = %+v; want rank 1", res)
+	}
+	if res := leader.UpdateScore("b", 20, base); res.New.Rank != 1 {
+		t.Errorf("leader UpdateScore(b) = %+v; want rank 1", res)
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range c.ids {
+		wg.Add(1)
+		go func(n *Node) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if _, err := n.IncrementScoreContext(context.Background(), "a", 1, base); err != nil {
+					t.Errorf("%s IncrementScoreContext() error = %v", n.id, err)
+					return
+				}
+			}
+		}(c.nodes[id])
+	}
+	wg.Wait()
+	if removed := follower.RemovePlayers("b", "missing"); removed != 1 {
+		t.Errorf("RemovePlayers(b, missing) = %d; want 1", removed)
+	}
+
+	// 线性一致读在任意节点上都能读到已提交的全部写操作
+	for _, id := range c.ids {
+		got := c.nodes[id].GetTopN(10)
+		if want := "[{a 70 1}]"; fmt.Sprint(got) != want {
+			t.Errorf("%s GetTopN() = %v; want %s", id, got, want)
+		}
+	}
+}
+
+func TestCluster_LeaderFailover(t *testing.T) {
+	c := newTestCluster(t, 3)
+	old := c.leader()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	old.UpdateScore("committed", 1, base)
+
+	// 旧领导者与集群断开后无法提交，写操作超时且不会生效
+	c.net.Disconnect(old.id)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	_, err := old.UpdateScoreContext(ctx, "lost", 1, base)
+	cancel()
+	if err == nil {
+		t.Fatal("UpdateScoreContext() on isolated leader succeeded")
+	}
+
+	leader := c.leader(old.id)
+	if leader.Status().Term <= old.Status().Term {
+		t.Errorf("new leader term %d; want greater than %d", leader.Status().Term, old.Status().Term)
+	}
+	if _, err := leader.UpdateScoreContext(context.Background(), "after", 2, base); err != nil {
+		t.Fatalf("UpdateScoreContext() on new leader error = %v", err)
+	}
+
+	// 旧领导者重启并重新连接后退位，未提交的日志被新领导者的日志覆盖
+	c.net.Connect(old.id)
+	restarted := c.start(old.id)
+	r, err := restarted.Linearizable(context.Background())
+	if err != nil {
+		t.Fatalf("Linearizable() after restart error = %v", err)
+	}
+	if got, want := fmt.Sprint(r.GetTopN(10)), "[{after 2 1} {committed 1 2}]"; got != want {
+		t.Errorf("restarted GetTopN() = %s; want %s", got, want)
+	}
+	if s := restarted.Status(); s.Role == Leader && s.Term <= leader.Status().Term {
+		t.Errorf("restarted node status = %+v; want follower or newer leader", s)
+	}
+}
+
+func TestCluster_LinearizableAndStaleReads(t *testing.T) {
+	c := newTestCluster(t, 3)
+	leader := c.leader()
+	isolated := c.follower(leader)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	leader.UpdateScore("a", 1, base)
+	if _, err := isolated.Linearizable(context.Background()); err != nil {
+		t.Fatalf("Linearizable() error = %v", err)
+	}
+
+	// 与集群断开的节点仍可本地读，但只能读到旧数据；线性一致读无法确认进度而失败
+	c.net.Disconnect(isolated.id)
+	leader.UpdateScore("a", 2, base)
+	if info, _ := isolated.Stale().GetPlayerRank("a"); info.Score != 1 {
+		t.Errorf("stale read on isolated node = %+v; want score 1", info)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	_, err := isolated.Linearizable(ctx)
+	cancel()
+	if !errors.Is(err, ErrNoLeader) {
+		t.Errorf("Linearizable() on isolated node error = %v; want ErrNoLeader", err)
+	}
+
+	// LeaderboardService 方法等待 RequestTimeout 后写操作返回错误，查询按 ReadMode 失败或退回本地排行榜
+	if res := isolated.UpdateScore("b", 1, base); !errors.Is(res.Err, ErrNoLeader) {
+		t.Errorf("UpdateScore() on isolated node = %+v; want ErrNoLeader", res)
+	}
+	if info, ok := isolated.GetPlayerRank("a"); ok || isolated.Status().FailedReads != 1 {
+		t.Errorf("linearizable read on isolated node = %+v, %v, FailedReads = %d; want not found and 1 failed read", info, ok, isolated.Status().FailedReads)
+	}
+	// 只有查询方法读取 ReadMode，测试中直接切换到允许退回本地读的模式
+	isolated.cfg.ReadMode = ReadLinearizableOrStale
+	if info, _ := isolated.GetPlayerRank("a"); info.Score != 1 || isolated.Status().StaleReads != 1 {
+		t.Errorf("fallback read on isolated node = %+v, StaleReads = %d; want score 1 and 1 stale read", info, isolated.Status().StaleReads)
+	}
+	isolated.cfg.ReadMode = ReadLinearizable
+
+	c.net.Connect(isolated.id)
+	if info, _ := isolated.GetPlayerRank("a"); info.Score != 2 {
+		t.Errorf("linearizable read after reconnect = %+v; want score 2", info)
+	}
+
+	// 单节点集群自己构成多数，配置为本地读时查询不与其他节点通信
+	solo := NewNode(Config{ID: "solo", Network: NewNetwork(), ReadMode: ReadStale, ElectionTimeout: 10 * time.Millisecond})
+	solo.Start()
+	defer solo.Stop()
+	var board leaderboard.LeaderboardService = solo
+	if res := board.UpdateScore("a", 3, base); res.New.Score != 3 || board.Len() != 1 {
+		t.Errorf("single node UpdateScore() = %+v, Len() = %d; want score 3 and 1 player", res, board.Len())
+	}
+}
+
+func TestNode_Conformance(t *testing.T) {
+	var nodes []*Node
+	t.Cleanup(func() {
+		for _, n := range nodes {
+			n.Stop()
+		}
+	})
+	// 单节点集群自己构成多数，写操作经过完整的提交和应用流程
+	leaderboardtest.Test(t, func(opts ...leaderboard.Option) leaderboard.LeaderboardService {
+		n := NewNode(Config{
+			ID:              fmt.Sprintf("node%d", len(nodes)),
+			Network:         NewNetwork(),
+			Options:         opts,
+			ElectionTimeout: 10 * time.Millisecond,
+		})
+		n.Start()
+		nodes = append(nodes, n)
+		return n
+	}, leaderboardtest.Config{Streams: 1})
+}
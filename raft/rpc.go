@@ -0,0 +1,156 @@
+package raft
+
+import (
+	"context"
+	"time"
+
+	"game/persist"
+)
+
+// voteRequest RequestVote 请求
+type voteRequest struct {
+	Term      uint64
+	Candidate string
+	LastIndex uint64 // 候选者最后一条日志的序号
+	LastTerm  uint64 // 候选者最后一条日志的任期
+}
+
+type voteReply struct {
+	Term    uint64
+	Granted bool
+}
+
+// appendRequest AppendEntries 请求，Entries 为空时即心跳
+type appendRequest struct {
+	Term      uint64
+	Leader    string
+	PrevIndex uint64 // Entries 之前一条日志的序号
+	PrevTerm  uint64 // Entries 之前一条日志的任期
+	Entries   []entry
+	Commit    uint64 // 领导者的提交进度
+}
+
+type appendReply struct {
+	Term      uint64
+	Success   bool
+	LastIndex uint64 // 失败时跟随者与领导者可能一致的最后一条日志序号，领导者从其后重试
+}
+
+// proposeRequest 跟随者转发给领导者的写操作
+type proposeRequest struct {
+	Record   persist.Record
+	Deadline time.Time // 转发方的超时时间，为零值时使用 RequestTimeout
+}
+
+type proposeReply struct {
+	Value interface{}
+	Err   error
+}
+
+// readIndexRequest 跟随者向领导者查询线性一致读的读进度
+type readIndexRequest struct {
+	Deadline time.Time
+}
+
+type readIndexReply struct {
+	Index uint64
+	Err   error
+}
+
+// handle 处理其他节点的请求，节点已停止时返回 nil
+func (n *Node) handle(req interface{}) interface{} {
+	switch req := req.(type) {
+	case *voteRequest:
+		return n.handleVote(req)
+	case *appendRequest:
+		return n.handleAppend(req)
+	case *proposeRequest:
+		ctx, cancel := n.requestContext(req.Deadline)
+		defer cancel()
+		value, err := n.proposeLocal(ctx, req.Record)
+		return &proposeReply{Value: value, Err: err}
+	case *readIndexRequest:
+		ctx, cancel := n.requestContext(req.Deadline)
+		defer cancel()
+		index, err := n.readIndexLocal(ctx)
+		return &readIndexReply{Index: index, Err: err}
+	}
+	return nil
+}
+
+func (n *Node) requestContext(deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.WithTimeout(context.Background(), n.cfg.RequestTimeout)
+	}
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+func (n *Node) handleVote(req *voteRequest) interface{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.stopped {
+		return nil
+	}
+	if req.Term > n.st.term {
+		n.becomeFollower(req.Term)
+	}
+	reply := &voteReply{Term: n.st.term}
+	if req.Term < n.st.term {
+		return reply
+	}
+	last := n.lastIndex()
+	lastTerm := n.st.log[last].Term
+	upToDate := req.LastTerm > lastTerm || (req.LastTerm == lastTerm && req.LastIndex >= last)
+	if (n.st.votedFor == "" || n.st.votedFor == req.Candidate) && upToDate {
+		n.st.votedFor = req.Candidate
+		reply.Granted = true
+		n.resetDeadline()
+	}
+	return reply
+}
+
+func (n *Node) handleAppend(req *appendRequest) interface{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.stopped {
+		return nil
+	}
+	last := n.lastIndex()
+	if req.Term < n.st.term {
+		return &appendReply{Term: n.st.term, LastIndex: last}
+	}
+	n.becomeFollower(req.Term)
+	n.leader = req.Leader
+	n.resetDeadline()
+
+	reply := &appendReply{Term: n.st.term}
+	if req.PrevIndex > last {
+		reply.LastIndex = last
+		return reply
+	}
+	if n.st.log[req.PrevIndex].Term != req.PrevTerm {
+		reply.LastIndex = req.PrevIndex - 1
+		return reply
+	}
+	for i, e := range req.Entries {
+		index := req.PrevIndex + 1 + uint64(i)
+		if index <= n.lastIndex() {
+			if n.st.log[index].Term == e.Term {
+				continue
+			}
+			n.st.log = n.st.log[:index] // 与领导者冲突的日志一定未提交，丢弃
+		}
+		n.st.log = append(n.st.log, req.Entries[i:]...)
+		break
+	}
+	// 只能提交已确认与领导者一致的日志
+	commit := req.Commit
+	if matched := req.PrevIndex + uint64(len(req.Entries)); commit > matched {
+		commit = matched
+	}
+	n.setCommit(commit)
+	reply.Success = true
+	return reply
+}
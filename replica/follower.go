@@ -0,0 +1,242 @@
+package replica
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"game/internal/notify"
+	"game/leaderboard"
+	"game/persist"
+)
+
+// Lag 从副本落后主副本的程度
+type Lag struct {
+	Applied   uint64        // 已应用的最后一条日志的序列号
+	HeadSeq   uint64        // 最近一次得知的主副本最后一条日志的序列号
+	Records   uint64        // 落后的日志条数，即 HeadSeq - Applied
+	Staleness time.Duration // 距最近一次确认已追上主副本经过的时间，从未追上时从 Start 起算
+	Connected bool          // 是否正在接收主副本的日志
+	Err       error         // 最近一次同步失败的原因，同步恢复后清空
+}
+
+// Follower 从副本，在后台通过 Source 与主副本同步，提供只读查询
+// 查询直接访问本地排行榜，结果可能落后于主副本，落后程度见 Lag；Wait 可以等待追上指定的序列号，实现读己之写
+type Follower struct {
+	leaderboard.LeaderboardReader
+
+	board  leaderboard.LeaderboardService
+	source Source
+	retry  time.Duration // 同步失败后重试的间隔
+	now    func() time.Time
+
+	mu        sync.Mutex
+	applied   uint64
+	head      uint64
+	loaded    bool      // 是否已经装载过快照
+	syncedAt  time.Time // 最近一次确认已追上主副本的时间
+	connected bool
+	err       error
+	stream    Stream // 当前的日志订阅，Close 时关闭以唤醒阻塞的 Recv
+
+	applies *notify.Broadcast // 应用日志后唤醒 Wait
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewFollower 创建从副本，board 为本地排行榜，配置必须与主副本的排行榜一致，其原有内容会被快照替换
+func NewFollower(board leaderboard.LeaderboardService, source Source) *Follower {
+	return &Follower{
+		LeaderboardReader: board,
+		board:             board,
+		source:            source,
+		retry:             time.Second,
+		now:               time.Now,
+		applies:           notify.NewBroadcast(),
+		stop:              make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+}
+
+// Start 开始在后台同步
+func (f *Follower) Start() {
+	f.mu.Lock()
+	f.syncedAt = f.now()
+	f.mu.Unlock()
+
+	go f.run()
+}
+
+// Close 停止同步并等待后台协程退出，本地排行榜保留最后的状态，仍可查询
+func (f *Follower) Close() {
+	f.mu.Lock()
+	select {
+	case <-f.stop:
+	default:
+		close(f.stop)
+	}
+	if f.stream != nil {
+		f.stream.Close()
+	}
+	f.mu.Unlock()
+
+	<-f.done
+}
+
+// Lag 返回落后主副本的程度
+func (f *Follower) Lag() Lag {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return Lag{
+		Applied:   f.applied,
+		HeadSeq:   f.head,
+		Records:   f.head - f.applied,
+		Staleness: f.now().Sub(f.syncedAt),
+		Connected: f.connected,
+		Err:       f.err,
+	}
+}
+
+// Wait 等待从副本应用到序列号 seq，用于在主副本写入后从从副本读到自己的写入
+func (f *Follower) Wait(ctx context.Context, seq uint64) error {
+	for {
+		applied := f.applies.Wait()
+		if f.Lag().Applied >= seq {
+			return nil
+		}
+		select {
+		case <-applied:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// run 后台同步循环，同步中断后按 retry 间隔重试，所需日志被丢弃时立即改为装载快照
+func (f *Follower) run() {
+	defer close(f.done)
+
+	for {
+		err := f.sync()
+		f.mu.Lock()
+		f.connected, f.stream, f.err = false, nil, err
+		f.mu.Unlock()
+
+		select {
+		case <-f.stop:
+			return
+		default:
+		}
+		if errors.Is(err, ErrLogTruncated) {
+			continue
+		}
+		select {
+		case <-f.stop:
+			return
+		case <-time.After(f.retry):
+		}
+	}
+}
+
+// sync 建立日志订阅并持续应用日志，直到订阅中断；第一次同步或所需日志已被丢弃时先装载快照
+func (f *Follower) sync() error {
+	f.mu.Lock()
+	loaded, from := f.loaded, f.applied+1
+	f.mu.Unlock()
+
+	stream, err := f.source.Stream(from)
+	if !loaded || errors.Is(err, ErrLogTruncated) {
+		if stream != nil {
+			stream.Close()
+		}
+		if err := f.loadSnapshot(); err != nil {
+			return err
+		}
+		f.mu.Lock()
+		from = f.applied + 1
+		f.mu.Unlock()
+		stream, err = f.source.Stream(from)
+	}
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	f.mu.Lock()
+	select {
+	case <-f.stop:
+		f.mu.Unlock()
+		return nil
+	default:
+	}
+	f.stream, f.connected, f.err = stream, true, nil
+	f.mu.Unlock()
+
+	for {
+		batch, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if err := f.apply(batch); err != nil {
+			return err
+		}
+	}
+}
+
+// loadSnapshot 从主副本获取快照替换本地排行榜
+func (f *Follower) loadSnapshot() error {
+	_, data, err := f.source.Snapshot()
+	if err != nil {
+		return err
+	}
+	seq, err := persist.ReadSnapshot(bytes.NewReader(data), f.board)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.applied, f.loaded = seq, true
+	if seq > f.head {
+		f.head = seq
+	}
+	f.mu.Unlock()
+	f.applies.Notify()
+	return nil
+}
+
+// apply 按序列号顺序应用一批日志，日志不连续时返回错误，由调用方重新订阅
+func (f *Follower) apply(batch Batch) error {
+	defer f.applies.Notify()
+
+	for _, e := range batch.Entries {
+		f.mu.Lock()
+		applied := f.applied
+		f.mu.Unlock()
+		if e.Seq <= applied {
+			continue // 装载快照与订阅之间重复推送的日志
+		}
+		if e.Seq != applied+1 {
+			return fmt.Errorf("replica: got record %d after %d", e.Seq, applied)
+		}
+		e.Apply(f.board)
+
+		f.mu.Lock()
+		f.applied = e.Seq
+		f.mu.Unlock()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if batch.HeadSeq > f.head {
+		f.head = batch.HeadSeq
+	}
+	if f.applied >= f.head {
+		f.syncedAt = f.now()
+	}
+	return nil
+}
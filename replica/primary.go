@@ -0,0 +1,157 @@
+// Package replica 实现排行榜的主从复制
+//
+// 主副本（Primary）包装一个 LeaderboardService，串行化所有写操作，为每个写操作分配连续递增的序列号，
+// 记录为复制日志后通知从副本。从副本（Follower）通过可替换的传输层（Source）先装载主副本的快照，
+// 再从快照之后的序列号开始按顺序应用日志，此后持续接收新的日志；从副本只提供只读查询，并报告落后主副本的程度。
+//
+// 主副本只在内存中保留最近的一段日志，从副本落后太多、所需日志已被丢弃时重新装载快照。
+// 日志记录的是操作本身而非结果，主从副本的排行榜必须使用相同的配置（实现无关，排序方向、排名策略、更新策略等一致）
+package replica
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"game/internal/notify"
+	"game/leaderboard"
+	"game/persist"
+)
+
+// ErrLogTruncated 请求的日志已被主副本丢弃，需要重新装载快照
+var ErrLogTruncated = errors.New("replica: log truncated")
+
+// Entry 复制日志中的一条记录
+type Entry struct {
+	persist.Record
+	Time time.Time // 主副本应用该记录的时间
+}
+
+// Primary 主副本，实现 LeaderboardService；读操作直接访问排行榜，写操作应用到排行榜后追加到复制日志
+type Primary struct {
+	leaderboard.LeaderboardService
+
+	mu      sync.Mutex // 串行化写操作，保证日志顺序与应用顺序一致
+	seq     uint64     // 最后一条日志的序列号
+	log     []Entry    // 内存中保留的日志，按序列号连续排列
+	maxLog  int        // 保留的日志条数
+	now     func() time.Time
+	changes *notify.Broadcast // 追加日志后唤醒等待的传输层
+}
+
+// NewPrimary 创建主副本，maxLog 为内存中保留的日志条数，小于等于 0 时使用默认值 10000
+// board 应为空排行榜，或者从副本都会先装载快照，因此也可以是已有数据的排行榜
+func NewPrimary(board leaderboard.LeaderboardService, maxLog int) *Primary {
+	if maxLog <= 0 {
+		maxLog = 10000
+	}
+	return &Primary{
+		LeaderboardService: board,
+		maxLog:             maxLog,
+		now:                time.Now,
+		changes:            notify.NewBroadcast(),
+	}
+}
+
+// UpdateScore 更新分数并记录日志
+func (p *Primary) UpdateScore(playerID string, score int, timestamp time.Time) leaderboard.UpdateResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	res := p.LeaderboardService.UpdateScore(playerID, score, timestamp)
+	p.append(persist.Record{Op: persist.OpUpdate, PlayerID: playerID, Score: score, Timestamp: timestamp})
+	return res
+}
+
+// IncrementScore 增加分数并记录日志
+func (p *Primary) IncrementScore(playerID string, delta int, timestamp time.Time) leaderboard.RankInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info := p.LeaderboardService.IncrementScore(playerID, delta, timestamp)
+	p.append(persist.Record{Op: persist.OpIncrement, PlayerID: playerID, Score: delta, Timestamp: timestamp})
+	return info
+}
+
+// RemovePlayer 删除玩家并记录日志
+func (p *Primary) RemovePlayer(playerID string) bool {
+	return p.RemovePlayers(playerID) == 1
+}
+
+// RemovePlayers 批量删除玩家并记录日志
+func (p *Primary) RemovePlayers(playerIDs ...string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	removed := p.LeaderboardService.RemovePlayers(playerIDs...)
+	p.append(persist.Record{Op: persist.OpRemove, PlayerIDs: append([]string(nil), playerIDs...)})
+	return removed
+}
+
+// Reset 清空排行榜并记录日志
+func (p *Primary) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.LeaderboardService.Reset()
+	p.append(persist.Record{Op: persist.OpReset})
+}
+
+// append 分配序列号并追加日志，超出保留条数时丢弃最早的日志，调用方需持有 p.mu
+func (p *Primary) append(rec persist.Record) {
+	p.seq++
+	rec.Seq = p.seq
+	p.log = append(p.log, Entry{Record: rec, Time: p.now()})
+	// 超出保留条数一倍后再整体搬移，均摊每次追加的开销
+	if len(p.log) >= 2*p.maxLog {
+		p.log = append([]Entry(nil), p.log[len(p.log)-p.maxLog:]...)
+	}
+	p.changes.Notify()
+}
+
+// Seq 返回最后一条日志的序列号
+func (p *Primary) Seq() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.seq
+}
+
+// Snapshot 返回排行榜的快照及其包含的最后一条日志的序列号，快照格式与 persist.WriteSnapshot 一致
+// 生成快照期间写操作会被阻塞
+func (p *Primary) Snapshot() (uint64, []byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := persist.WriteSnapshot(&buf, p.LeaderboardService, p.seq); err != nil {
+		return 0, nil, err
+	}
+	return p.seq, buf.Bytes(), nil
+}
+
+// Entries 返回从 fromSeq 开始的最多 limit 条日志和最后一条日志的序列号
+// fromSeq 之前的日志已被丢弃时返回 ErrLogTruncated；没有新日志时返回空列表
+func (p *Primary) Entries(fromSeq uint64, limit int) ([]Entry, uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if fromSeq > p.seq {
+		return nil, p.seq, nil
+	}
+	first := p.seq + 1 - uint64(len(p.log))
+	if fromSeq < first {
+		return nil, p.seq, ErrLogTruncated
+	}
+	entries := p.log[fromSeq-first:]
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return append([]Entry(nil), entries...), p.seq, nil
+}
+
+// Changed 返回下一次追加日志时关闭的通道，应在调用 Entries 之前取得
+func (p *Primary) Changed() <-chan struct{} {
+	return p.changes.Wait()
+}
@@ -0,0 +1,149 @@
+package replica
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"game/leaderboard"
+	"game/leaderboard/leaderboardtest"
+)
+
+// newTestFollower 创建连接到 primary 的从副本，缩短心跳和重试间隔以加快测试
+func newTestFollower(primary *Primary) (*Follower, *MemoryTransport) {
+	transport := NewMemoryTransport(primary)
+	transport.heartbeat = 10 * time.Millisecond
+	f := NewFollower(leaderboard.NewLeaderboardLinkedList(), transport)
+	f.retry = 10 * time.Millisecond
+	return f, transport
+}
+
+func waitFor(t *testing.T, f *Follower, seq uint64) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := f.Wait(ctx, seq); err != nil {
+		t.Fatalf("Wait(%d) = %v; lag %+v", seq, err, f.Lag())
+	}
+}
+
+func TestFollower_SnapshotAndLogCatchUp(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	board := leaderboard.NewLeaderboardSkipList()
+	board.UpdateScore("existing", 500, base) // 复制开始前已有的数据只能通过快照获得
+	primary := NewPrimary(board, 10)
+
+	// 写入远超保留条数的日志，从副本必须先装载快照
+	for i := 0; i < 100; i++ {
+		primary.UpdateScore(fmt.Sprintf("p%d", i%30), i, base.Add(time.Duration(i)*time.Second))
+	}
+	f, _ := newTestFollower(primary)
+	f.Start()
+	defer f.Close()
+	waitFor(t, f, primary.Seq())
+
+	// 追上之后继续按日志同步各类写操作
+	primary.IncrementScore("p1", 1000, base)
+	primary.RemovePlayers("p2", "p3", "missing")
+	primary.UpdateScore("new", 7, base)
+	waitFor(t, f, primary.Seq())
+
+	if got, want := f.GetTopN(100), primary.GetTopN(100); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("follower GetTopN() = %+v; want %+v", got, want)
+	}
+	got, _ := f.GetPlayerRank("existing")
+	if want, _ := primary.GetPlayerRank("existing"); got != want {
+		t.Errorf("follower GetPlayerRank(existing) = %+v; want %+v", got, want)
+	}
+	lag := f.Lag()
+	if lag.Applied != primary.Seq() || lag.Records != 0 || !lag.Connected || lag.Err != nil {
+		t.Errorf("Lag() = %+v; want caught up at %d", lag, primary.Seq())
+	}
+
+	primary.Reset()
+	waitFor(t, f, primary.Seq())
+	if n := f.Len(); n != 0 {
+		t.Errorf("follower Len() after Reset = %d; want 0", n)
+	}
+}
+
+func TestFollower_DisconnectLagAndRecovery(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	primary := NewPrimary(leaderboard.NewLeaderboardSkipList(), 5)
+	f, transport := newTestFollower(primary)
+	var mu sync.Mutex
+	now := base
+	f.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	f.Start()
+	defer f.Close()
+
+	primary.UpdateScore("a", 1, base)
+	waitFor(t, f, 1)
+
+	transport.Disconnect()
+	for i := 0; i < 20; i++ {
+		primary.IncrementScore("a", 1, base)
+	}
+	mu.Lock()
+	now = now.Add(time.Minute)
+	mu.Unlock()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for f.Lag().Connected {
+		if time.Now().After(deadline) {
+			t.Fatal("follower still connected after Disconnect")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	lag := f.Lag()
+	if lag.Applied != 1 || lag.Err == nil || lag.Staleness < time.Minute {
+		t.Errorf("Lag() while disconnected = %+v; want applied 1, error and staleness >= 1m", lag)
+	}
+	if got, _ := f.GetPlayerRank("a"); got.Score != 1 {
+		t.Errorf("follower score while disconnected = %d; want stale value 1", got.Score)
+	}
+
+	// 断开期间的日志已被丢弃，恢复后通过快照追上
+	transport.Reconnect()
+	waitFor(t, f, primary.Seq())
+	if got, _ := f.GetPlayerRank("a"); got.Score != 21 {
+		t.Errorf("follower score after Reconnect = %d; want 21", got.Score)
+	}
+	// 追上后的心跳确认同步时间，Staleness 归零
+	for f.Lag().Staleness != 0 || f.Lag().Err != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("Lag() after Reconnect = %+v; want fresh", f.Lag())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPrimary_Entries(t *testing.T) {
+	primary := NewPrimary(leaderboard.NewLeaderboardSkipList(), 3)
+	for i := 0; i < 7; i++ {
+		primary.UpdateScore("a", i, time.Time{})
+	}
+	// 日志在达到保留条数两倍时裁剪到 3 条，此时保留 4-7
+	if _, _, err := primary.Entries(3, 0); err != ErrLogTruncated {
+		t.Errorf("Entries(3) error = %v; want ErrLogTruncated", err)
+	}
+	entries, head, err := primary.Entries(5, 2)
+	if err != nil || head != 7 || len(entries) != 2 || entries[0].Seq != 5 || entries[1].Score != 5 {
+		t.Errorf("Entries(5, 2) = %+v, %d, %v; want seq 5-6 with head 7", entries, head, err)
+	}
+	if entries, head, err := primary.Entries(8, 0); err != nil || head != 7 || len(entries) != 0 {
+		t.Errorf("Entries(8) = %+v, %d, %v; want empty with head 7", entries, head, err)
+	}
+}
+
+func TestPrimary_Conformance(t *testing.T) {
+	leaderboardtest.Test(t, func(opts ...leaderboard.Option) leaderboard.LeaderboardService {
+		return NewPrimary(leaderboard.NewLeaderboardSkipList(opts...), 0)
+	}, leaderboardtest.Config{Streams: 1})
+}
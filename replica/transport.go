@@ -0,0 +1,152 @@
+package replica
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrUnavailable 传输层暂时无法连接主副本，从副本稍后重试
+var ErrUnavailable = errors.New("replica: primary unavailable")
+
+// Batch 传输层一次推送给从副本的日志
+// 没有新日志时传输层定期推送空的 Batch 作为心跳，让从副本得知主副本的最新序列号
+type Batch struct {
+	Entries []Entry   // 按序列号连续排列的日志，可以为空
+	HeadSeq uint64    // 发送时主副本最后一条日志的序列号
+	Time    time.Time // 发送时主副本的时间
+}
+
+// Source 从副本访问主副本的传输层，可以替换为基于网络的实现
+type Source interface {
+	Snapshot() (seq uint64, data []byte, err error) // 获取主副本的快照，见 Primary.Snapshot
+	Stream(fromSeq uint64) (Stream, error)          // 从 fromSeq 开始订阅日志，所需日志已被丢弃时返回 ErrLogTruncated
+}
+
+// Stream 日志订阅
+type Stream interface {
+	Recv() (Batch, error) // 阻塞直到有新日志或需要发送心跳，连接断开时返回错误
+	Close() error
+}
+
+// MemoryTransport 进程内的传输层，直接访问同一进程中的主副本，用于测试和单机部署
+// Disconnect 可以模拟网络中断：已建立的订阅返回 ErrUnavailable，新的请求失败，直到调用 Reconnect
+type MemoryTransport struct {
+	primary   *Primary
+	heartbeat time.Duration // 没有新日志时发送心跳的间隔
+	batchSize int           // 单次推送的最大日志条数
+
+	mu   sync.Mutex
+	down chan struct{} // 断开时关闭，连接正常时为未关闭的通道
+	cut  bool
+}
+
+// NewMemoryTransport 创建连接到 primary 的进程内传输层
+func NewMemoryTransport(primary *Primary) *MemoryTransport {
+	return &MemoryTransport{
+		primary:   primary,
+		heartbeat: time.Second,
+		batchSize: 1000,
+		down:      make(chan struct{}),
+	}
+}
+
+// Disconnect 断开连接，已建立的订阅立即返回 ErrUnavailable
+func (t *MemoryTransport) Disconnect() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.cut {
+		t.cut = true
+		close(t.down)
+	}
+}
+
+// Reconnect 恢复连接
+func (t *MemoryTransport) Reconnect() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cut {
+		t.cut = false
+		t.down = make(chan struct{})
+	}
+}
+
+// conn 返回当前连接断开时关闭的通道，连接已断开时返回 ErrUnavailable
+func (t *MemoryTransport) conn() (<-chan struct{}, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cut {
+		return nil, ErrUnavailable
+	}
+	return t.down, nil
+}
+
+// Snapshot 获取主副本的快照
+func (t *MemoryTransport) Snapshot() (uint64, []byte, error) {
+	if _, err := t.conn(); err != nil {
+		return 0, nil, err
+	}
+	return t.primary.Snapshot()
+}
+
+// Stream 从 fromSeq 开始订阅日志
+func (t *MemoryTransport) Stream(fromSeq uint64) (Stream, error) {
+	down, err := t.conn()
+	if err != nil {
+		return nil, err
+	}
+	// 提前检查日志是否已被丢弃，与网络实现在建立订阅时返回错误的行为一致
+	if _, _, err := t.primary.Entries(fromSeq, 1); err != nil {
+		return nil, err
+	}
+	return &memoryStream{t: t, next: fromSeq, down: down, closed: make(chan struct{})}, nil
+}
+
+type memoryStream struct {
+	t         *MemoryTransport
+	next      uint64 // 下一条要推送的日志的序列号
+	down      <-chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *memoryStream) Recv() (Batch, error) {
+	timer := time.NewTimer(s.t.heartbeat)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.down:
+			return Batch{}, ErrUnavailable
+		default:
+		}
+		// 先取通知通道再读取日志，保证读取之后追加的日志一定能唤醒本次等待
+		changed := s.t.primary.Changed()
+		entries, head, err := s.t.primary.Entries(s.next, s.t.batchSize)
+		if err != nil {
+			return Batch{}, err
+		}
+		if len(entries) > 0 {
+			s.next = entries[len(entries)-1].Seq + 1
+			return Batch{Entries: entries, HeadSeq: head, Time: s.t.primary.now()}, nil
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return Batch{HeadSeq: head, Time: s.t.primary.now()}, nil
+		case <-s.down:
+			return Batch{}, ErrUnavailable
+		case <-s.closed:
+			return Batch{}, errors.New("replica: stream closed")
+		}
+	}
+}
+
+func (s *memoryStream) Close() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	return nil
+}
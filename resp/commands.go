@@ -0,0 +1,394 @@
+package resp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"game/leaderboard"
+)
+
+const (
+	errSyntax     = "ERR syntax error"
+	errNotInteger = "ERR value is not an integer or out of range"
+	errMinMax     = "ERR min or max is not a float"
+	errNoSuchKey  = "ERR no such leaderboard"
+	errBoardFull  = "ERR leaderboard is full"
+)
+
+// writeFailed 写操作失败（例如预写日志写入失败）时的错误回复
+func writeFailed(err error) string {
+	return "ERR write failed: " + err.Error()
+}
+
+// command 命令定义，arity 与 Redis 的约定一致：正数表示参数个数（含命令名）必须相等，负数表示至少为其绝对值
+type command struct {
+	arity   int
+	handler func(s *Server, wr *writer, args []string)
+}
+
+var commands = map[string]command{
+	"ping":      {-1, (*Server).ping},
+	"command":   {-1, (*Server).command},
+	"zadd":      {-4, (*Server).zadd},
+	"zincrby":   {4, (*Server).zincrby},
+	"zrevrank":  {-3, (*Server).zrevrank},
+	"zrevrange": {-4, (*Server).zrevrange},
+	"zscore":    {3, (*Server).zscore},
+	"zrem":      {-3, (*Server).zrem},
+	"zcard":     {2, (*Server).zcard},
+	"zcount":    {4, (*Server).zcount},
+}
+
+// execute 校验参数个数后执行命令
+func (s *Server) execute(wr *writer, args []string) {
+	name := strings.ToLower(args[0])
+	cmd, ok := commands[name]
+	if !ok {
+		wr.error(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+		return
+	}
+	if (cmd.arity > 0 && len(args) != cmd.arity) || (cmd.arity < 0 && len(args) < -cmd.arity) {
+		wr.error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", name))
+		return
+	}
+	cmd.handler(s, wr, args)
+}
+
+// ping PING [message]
+func (s *Server) ping(wr *writer, args []string) {
+	switch len(args) {
+	case 1:
+		wr.simple("PONG")
+	case 2:
+		wr.bulk(args[1])
+	default:
+		wr.error("ERR wrong number of arguments for 'ping' command")
+	}
+}
+
+// command COMMAND，redis-cli 连接时会发送，返回空列表即可
+func (s *Server) command(wr *writer, args []string) {
+	wr.array(0)
+}
+
+// zadd ZADD key [NX|XX] [GT|LT] [CH] score member [score member ...]
+// 返回新增的成员数，指定 CH 时返回新增或分数发生变化的成员数
+func (s *Server) zadd(wr *writer, args []string) {
+	var nx, xx, gt, lt, ch bool
+	i := 2
+flags:
+	for ; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		case "gt":
+			gt = true
+		case "lt":
+			lt = true
+		case "ch":
+			ch = true
+		default:
+			break flags
+		}
+	}
+	pairs := args[i:]
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		wr.error(errSyntax)
+		return
+	}
+	if (nx && xx) || (gt && lt) || (nx && (gt || lt)) {
+		wr.error("ERR GT, LT, and/or NX options at the same time are not compatible")
+		return
+	}
+	scores := make([]int, 0, len(pairs)/2)
+	for j := 0; j < len(pairs); j += 2 {
+		score, ok := parseScore(pairs[j])
+		if !ok {
+			wr.error(errNotInteger)
+			return
+		}
+		scores = append(scores, score)
+	}
+	board, ok := s.lookup(args[1])
+	if !ok {
+		wr.error(errNoSuchKey)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	now := s.now()
+	count := 0
+	for j, score := range scores {
+		member := pairs[2*j+1]
+		old, exists := board.GetPlayerRank(member)
+		if (nx && exists) || (xx && !exists) {
+			continue
+		}
+		if exists && ((gt && score <= old.Score) || (lt && score >= old.Score)) {
+			continue
+		}
+		res, err := leaderboard.UpdateScoreContext(context.Background(), board, member, score, now)
+		if err != nil {
+			wr.error(writeFailed(err)) // 之前的成员已经写入，与 Redis 一样不回滚
+			return
+		}
+		if res.Rejected {
+			continue
+		}
+		if !res.Existed || (ch && res.ScoreChanged) {
+			count++
+		}
+	}
+	wr.integer(count)
+}
+
+// zincrby ZINCRBY key increment member，返回增加后的分数
+func (s *Server) zincrby(wr *writer, args []string) {
+	delta, ok := parseScore(args[2])
+	if !ok {
+		wr.error(errNotInteger)
+		return
+	}
+	board, ok := s.lookup(args[1])
+	if !ok {
+		wr.error(errNoSuchKey)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	info, err := leaderboard.IncrementScoreContext(context.Background(), board, args[3], delta, s.now())
+	if err != nil {
+		wr.error(writeFailed(err))
+		return
+	}
+	if info.PlayerID == "" {
+		wr.error(errBoardFull)
+		return
+	}
+	wr.bulk(strconv.Itoa(info.Score))
+}
+
+// zrevrank ZREVRANK key member [WITHSCORE]，返回从 0 开始的名次，成员不存在时返回 nil
+func (s *Server) zrevrank(wr *writer, args []string) {
+	withScore := false
+	if len(args) == 4 && strings.EqualFold(args[3], "withscore") {
+		withScore = true
+	} else if len(args) != 3 {
+		wr.error(errSyntax)
+		return
+	}
+	board, ok := s.lookup(args[1])
+	if !ok {
+		wr.null()
+		return
+	}
+	info, ok := board.GetPlayerRank(args[2])
+	if !ok {
+		wr.null()
+		return
+	}
+	if withScore {
+		wr.array(2)
+		wr.integer(info.Rank - 1)
+		wr.bulk(strconv.Itoa(info.Score))
+		return
+	}
+	wr.integer(info.Rank - 1)
+}
+
+// zrevrange ZREVRANGE key start stop [WITHSCORES]，下标从 0 开始，负数表示从末尾倒数
+func (s *Server) zrevrange(wr *writer, args []string) {
+	withScores := false
+	if len(args) == 5 && strings.EqualFold(args[4], "withscores") {
+		withScores = true
+	} else if len(args) != 4 {
+		wr.error(errSyntax)
+		return
+	}
+	start, err1 := strconv.Atoi(args[2])
+	stop, err2 := strconv.Atoi(args[3])
+	if err1 != nil || err2 != nil {
+		wr.error(errNotInteger)
+		return
+	}
+	board, ok := s.lookup(args[1])
+	if !ok {
+		wr.array(0)
+		return
+	}
+
+	n := board.Len()
+	if start < 0 {
+		start = max(start+n, 0)
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if stop >= n {
+		stop = n - 1 // 先截断到末尾再转换为名次，避免 stop 为 math.MaxInt 时加 1 溢出
+	}
+	var infos []leaderboard.RankInfo
+	if start <= stop && start < n {
+		infos = board.GetRankRange(start+1, stop+1)
+	}
+
+	if withScores {
+		wr.array(2 * len(infos))
+	} else {
+		wr.array(len(infos))
+	}
+	for _, info := range infos {
+		wr.bulk(info.PlayerID)
+		if withScores {
+			wr.bulk(strconv.Itoa(info.Score))
+		}
+	}
+}
+
+// zscore ZSCORE key member，成员不存在时返回 nil
+func (s *Server) zscore(wr *writer, args []string) {
+	board, ok := s.lookup(args[1])
+	if !ok {
+		wr.null()
+		return
+	}
+	info, ok := board.GetPlayerRank(args[2])
+	if !ok {
+		wr.null()
+		return
+	}
+	wr.bulk(strconv.Itoa(info.Score))
+}
+
+// zrem ZREM key member [member ...]，返回实际删除的成员数
+func (s *Server) zrem(wr *writer, args []string) {
+	board, ok := s.lookup(args[1])
+	if !ok {
+		wr.integer(0)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	removed, err := leaderboard.RemovePlayersContext(context.Background(), board, args[2:]...)
+	if err != nil {
+		wr.error(writeFailed(err))
+		return
+	}
+	wr.integer(removed)
+}
+
+// zcard ZCARD key，返回成员数
+func (s *Server) zcard(wr *writer, args []string) {
+	board, ok := s.lookup(args[1])
+	if !ok {
+		wr.integer(0)
+		return
+	}
+	wr.integer(board.Len())
+}
+
+// zcount ZCOUNT key min max，min、max 支持 -inf、+inf 和表示开区间的 ( 前缀
+func (s *Server) zcount(wr *writer, args []string) {
+	r, ok := parseScoreRange(args[2], args[3])
+	if !ok {
+		wr.error(errMinMax)
+		return
+	}
+	board, ok := s.lookup(args[1])
+	if !ok {
+		wr.integer(0)
+		return
+	}
+	wr.integer(board.CountByScore(r))
+}
+
+// parseScore 解析整数分数，也接受客户端库把整数格式化成的浮点数形式（如 "100.0"、"1e3"）
+func parseScore(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// parseScoreRange 把 ZCOUNT 的 min、max 参数转换为整数分数的闭区间
+// 分数都是整数，因此非整数边界向区间内侧取整，开区间的整数边界向内收缩 1；
+// 边界收缩后超出整数范围（例如 (9223372036854775807 作为下界）时没有分数能落在区间内，返回空区间
+func parseScoreRange(minArg, maxArg string) (leaderboard.ScoreRange, bool) {
+	lo, loEmpty, ok := parseBound(minArg, true)
+	if !ok {
+		return leaderboard.ScoreRange{}, false
+	}
+	hi, hiEmpty, ok := parseBound(maxArg, false)
+	if !ok {
+		return leaderboard.ScoreRange{}, false
+	}
+	if loEmpty || hiEmpty {
+		return leaderboard.ScoreBetween(1, 0), true // 下界大于上界，不包含任何分数
+	}
+	return leaderboard.ScoreBetween(lo, hi), true
+}
+
+// parseBound 解析区间边界，lower 表示下界；empty 表示边界超出整数范围，区间内不可能有分数
+func parseBound(s string, lower bool) (n int, empty, ok bool) {
+	exclusive := strings.HasPrefix(s, "(")
+	if exclusive {
+		s = s[1:]
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		switch {
+		case exclusive && lower:
+			if n == math.MaxInt {
+				return 0, true, true
+			}
+			n++
+		case exclusive && !lower:
+			if n == math.MinInt {
+				return 0, true, true
+			}
+			n--
+		}
+		return n, false, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, false, false
+	}
+	if lower {
+		if exclusive {
+			f = math.Floor(f) + 1
+		} else {
+			f = math.Ceil(f)
+		}
+	} else {
+		if exclusive {
+			f = math.Ceil(f) - 1
+		} else {
+			f = math.Floor(f)
+		}
+	}
+	// float64(math.MaxInt64) 为 2^63，已经超出 int 的范围
+	switch {
+	case lower && f >= math.MaxInt64, !lower && f < math.MinInt64:
+		return 0, true, true
+	case f <= math.MinInt64:
+		return math.MinInt, false, true
+	case f >= math.MaxInt64:
+		return math.MaxInt, false, true
+	}
+	return int(f), false, true
+}
@@ -0,0 +1,136 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxArgs    = 1 << 20 // 单条命令的最大参数个数
+	maxBulkLen = 1 << 20 // 单个参数的最大字节数
+	maxLineLen = 1 << 16 // 协议行（含内联命令）的最大字节数
+)
+
+// errProtocol 客户端发送的数据不符合 RESP 协议，回复错误后关闭连接
+var errProtocol = errors.New("Protocol error")
+
+// reader 解析客户端发送的命令
+// 支持 RESP 数组形式（*<参数个数>\r\n$<长度>\r\n<参数>\r\n...）和按空白分隔的内联命令
+type reader struct {
+	r *bufio.Reader
+}
+
+func newReader(r io.Reader) *reader {
+	return &reader{r: bufio.NewReader(r)}
+}
+
+// buffered 返回已读入缓冲区但尚未解析的字节数，用于判断客户端是否还有流水线命令
+func (rd *reader) buffered() int {
+	return rd.r.Buffered()
+}
+
+// readCommand 读取一条命令，返回命令名和参数；空行返回空切片
+func (rd *reader) readCommand() ([]string, error) {
+	line, err := rd.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n > maxArgs {
+		return nil, fmt.Errorf("%w: invalid multibulk length", errProtocol)
+	}
+	args := make([]string, 0, max(n, 0))
+	for i := 0; i < n; i++ {
+		line, err := rd.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(line, "$") {
+			return nil, fmt.Errorf("%w: expected '$', got '%.1s'", errProtocol, line)
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 || size > maxBulkLen {
+			return nil, fmt.Errorf("%w: invalid bulk length", errProtocol)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(rd.r, buf); err != nil {
+			return nil, err
+		}
+		if buf[size] != '\r' || buf[size+1] != '\n' {
+			return nil, fmt.Errorf("%w: bulk string not terminated by CRLF", errProtocol)
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+// readLine 读取一行并去掉行尾的 \r\n
+func (rd *reader) readLine() (string, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := rd.r.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		line = append(line, chunk...)
+		if len(line) > maxLineLen {
+			return "", fmt.Errorf("%w: too big inline request", errProtocol)
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
+
+// writer 按 RESP2 格式写出回复
+type writer struct {
+	w *bufio.Writer
+}
+
+func newWriter(w io.Writer) *writer {
+	return &writer{w: bufio.NewWriter(w)}
+}
+
+func (wr *writer) simple(s string) {
+	wr.w.WriteString("+" + s + "\r\n")
+}
+
+func (wr *writer) error(msg string) {
+	wr.w.WriteString("-" + msg + "\r\n")
+}
+
+func (wr *writer) integer(n int) {
+	wr.w.WriteString(":" + strconv.Itoa(n) + "\r\n")
+}
+
+func (wr *writer) bulk(s string) {
+	wr.w.WriteString("$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n")
+}
+
+// null 写出空回复（RESP2 中的 nil bulk string）
+func (wr *writer) null() {
+	wr.w.WriteString("$-1\r\n")
+}
+
+func (wr *writer) array(n int) {
+	wr.w.WriteString("*" + strconv.Itoa(n) + "\r\n")
+}
+
+func (wr *writer) flush() error {
+	return wr.w.Flush()
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
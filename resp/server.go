@@ -0,0 +1,169 @@
+// Package resp 以 Redis 协议（RESP）对外提供 LeaderboardService，redis-cli 和现有的 Redis 客户端库可以直接访问排行榜
+//
+// 支持的命令是有序集合命令的一个子集：ZADD、ZINCRBY、ZREVRANK、ZREVRANGE、ZSCORE、ZREM、ZCARD、ZCOUNT，
+// 以及连接相关的 PING、QUIT、COMMAND。命令中的 key 通过 BoardLookup 映射到排行榜，与 Redis 的区别如下：
+//
+//   - 分数为整数，非整数分数返回错误
+//   - ZREVRANK、ZREVRANGE 按排行榜的名次顺序返回（名次 1 对应下标 0），与排行榜的排序方向一致
+//   - ZREVRANK 返回 RankInfo.Rank-1，采用非序数排名策略时同分玩家的下标相同
+//   - ZADD、ZINCRBY 以服务器收到命令的时间作为得分时间戳，同分玩家按排行榜的时间戳规则排序
+//   - key 对应的排行榜不存在时，读命令按空集合处理，写命令返回错误
+//   - 排行榜达到玩家数上限时，ZADD 跳过新成员且不计入返回值，ZINCRBY 对新成员返回错误
+package resp
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"game/leaderboard"
+)
+
+// ErrServerClosed 服务器已关闭
+var ErrServerClosed = errors.New("resp: server closed")
+
+// BoardLookup 根据命令中的 key 查找排行榜，不存在时返回 false
+type BoardLookup func(key string) (leaderboard.LeaderboardService, bool)
+
+// SingleBoard 返回把所有 key 都映射到同一个排行榜的 BoardLookup
+func SingleBoard(board leaderboard.LeaderboardService) BoardLookup {
+	return func(string) (leaderboard.LeaderboardService, bool) {
+		return board, true
+	}
+}
+
+// Server RESP 协议服务器
+type Server struct {
+	lookup BoardLookup
+	now    func() time.Time // 写命令使用的时钟
+
+	writeMu sync.Mutex // 串行化写命令，保证 ZADD 的 NX、XX、GT、LT 条件判断与写入之间不被其他连接打断
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// NewServer 创建 RESP 服务器
+func NewServer(lookup BoardLookup) *Server {
+	return &Server{
+		lookup:    lookup,
+		now:       time.Now,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe 监听 TCP 地址 addr 并处理连接
+func (s *Server) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve 接受 ln 上的连接，每个连接由独立的协程处理，直到 Close 后返回 ErrServerClosed
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+	s.listeners[ln] = struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			delete(s.listeners, ln)
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// Close 关闭所有监听器和连接，等待连接处理协程退出
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	for ln := range s.listeners {
+		ln.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// serveConn 循环读取并执行命令；客户端没有更多流水线命令时才刷新回复，减少系统调用
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	rd := newReader(conn)
+	wr := newWriter(conn)
+	for {
+		args, err := rd.readCommand()
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				wr.error("ERR " + err.Error())
+				wr.flush()
+			} else if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Printf("resp: read from %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		quit := strings.EqualFold(args[0], "quit")
+		if quit {
+			wr.simple("OK")
+		} else {
+			s.execute(wr, args)
+		}
+		if quit || rd.buffered() == 0 {
+			if err := wr.flush(); err != nil || quit {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package resp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"game/leaderboard"
+)
+
+// testClient 最小的 RESP 客户端，把回复解码为 string、int、nil、error 或 []interface{}
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func startServer(t *testing.T, lookup BoardLookup) *testClient {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(lookup)
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ln) }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		srv.Close()
+		if err := <-done; err != ErrServerClosed {
+			t.Errorf("Serve returned %v, want ErrServerClosed", err)
+		}
+	})
+	return &testClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *testClient) do(args ...string) interface{} {
+	c.t.Helper()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.conn.Write([]byte(sb.String())); err != nil {
+		c.t.Fatal(err)
+	}
+	return c.read()
+}
+
+func (c *testClient) read() interface{} {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	switch line[0] {
+	case '+':
+		return line[1:]
+	case '-':
+		return fmt.Errorf("%s", line[1:])
+	case ':':
+		n, _ := strconv.Atoi(line[1:])
+		return n
+	case '$':
+		n, _ := strconv.Atoi(line[1:])
+		if n < 0 {
+			return nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			c.t.Fatal(err)
+		}
+		return string(buf[:n])
+	case '*':
+		n, _ := strconv.Atoi(line[1:])
+		items := []interface{}{}
+		for i := 0; i < n; i++ {
+			items = append(items, c.read())
+		}
+		return items
+	}
+	c.t.Fatalf("unexpected reply %q", line)
+	return nil
+}
+
+func (c *testClient) expect(want interface{}, args ...string) {
+	c.t.Helper()
+	got := c.do(args...)
+	if err, ok := got.(error); ok {
+		got = err.Error()
+		if s, ok := want.(string); !ok || !strings.HasPrefix(s, "ERR") {
+			c.t.Errorf("%v: unexpected error %v", args, err)
+			return
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		c.t.Errorf("%v: got %#v, want %#v", args, got, want)
+	}
+}
+
+func TestSortedSetCommands(t *testing.T) {
+	c := startServer(t, SingleBoard(leaderboard.NewLeaderboardSkipList()))
+
+	c.expect("PONG", "PING")
+	c.expect(3, "ZADD", "lb", "100", "A", "90", "B", "90", "C")
+	c.expect(0, "ZADD", "lb", "100", "A")
+	c.expect(2, "ZADD", "lb", "CH", "95", "A", "80", "D")
+	c.expect(0, "ZADD", "lb", "NX", "1", "A")
+	c.expect(0, "ZADD", "lb", "XX", "1", "E")
+	c.expect(0, "ZADD", "lb", "GT", "CH", "50", "D")
+	c.expect(1, "ZADD", "lb", "LT", "CH", "70", "D")
+	c.expect(4, "ZCARD", "lb")
+
+	// 同分时先得到该分数的玩家排在前面
+	c.expect([]interface{}{"A", "95", "B", "90", "C", "90", "D", "70"}, "ZREVRANGE", "lb", "0", "-1", "WITHSCORES")
+	c.expect([]interface{}{"C", "D"}, "ZREVRANGE", "lb", "-2", "10")
+	c.expect([]interface{}{}, "ZREVRANGE", "lb", "5", "10")
+	c.expect([]interface{}{"B", "C", "D"}, "ZREVRANGE", "lb", "1", "9223372036854775807")
+	c.expect(2, "ZREVRANK", "lb", "C")
+	c.expect([]interface{}{1, "90"}, "ZREVRANK", "lb", "B", "WITHSCORE")
+	c.expect(nil, "ZREVRANK", "lb", "X")
+
+	c.expect("100", "ZINCRBY", "lb", "10", "C")
+	c.expect(0, "ZREVRANK", "lb", "C")
+	c.expect("100", "ZSCORE", "lb", "C")
+	c.expect(nil, "ZSCORE", "lb", "X")
+	c.expect("5", "ZINCRBY", "lb", "5", "E")
+
+	c.expect(5, "ZCOUNT", "lb", "-inf", "+inf")
+	c.expect(2, "ZCOUNT", "lb", "90", "(100")
+	c.expect(2, "ZCOUNT", "lb", "(89.5", "99.9")
+	c.expect(0, "ZCOUNT", "lb", "(100", "100")
+
+	// 整数上下限处的开区间边界没有可取的分数
+	c.expect(2, "ZADD", "lb", "9223372036854775807", "max", "-9223372036854775808", "min")
+	c.expect(1, "ZCOUNT", "lb", "9223372036854775807", "+inf")
+	c.expect(0, "ZCOUNT", "lb", "(9223372036854775807", "+inf")
+	c.expect(1, "ZCOUNT", "lb", "-inf", "-9223372036854775808")
+	c.expect(0, "ZCOUNT", "lb", "-inf", "(-9223372036854775808")
+	c.expect(0, "ZCOUNT", "lb", "+inf", "+inf")
+	c.expect(2, "ZREM", "lb", "max", "min")
+
+	c.expect(2, "ZREM", "lb", "D", "E", "X")
+	c.expect(3, "ZCARD", "lb")
+}
+
+func TestCommandErrors(t *testing.T) {
+	c := startServer(t, func(key string) (leaderboard.LeaderboardService, bool) {
+		return nil, false
+	})
+
+	c.expect("ERR unknown command 'FOO'", "FOO")
+	c.expect("ERR wrong number of arguments for 'zscore' command", "ZSCORE", "lb")
+	c.expect(errSyntax, "ZADD", "lb", "1", "A", "2")
+	c.expect(errNotInteger, "ZADD", "lb", "1.5", "A")
+	c.expect(errMinMax, "ZCOUNT", "lb", "a", "1")
+	c.expect(errNoSuchKey, "ZADD", "lb", "1", "A")
+	c.expect(0, "ZCARD", "lb")
+	c.expect(nil, "ZSCORE", "lb", "A")
+}
+
+func TestInlineAndPipeline(t *testing.T) {
+	c := startServer(t, SingleBoard(leaderboard.NewLeaderboardLinkedList()))
+
+	// 内联命令和流水线：一次写入多条命令，依次读取回复
+	if _, err := c.conn.Write([]byte("ZADD lb 1 A\r\nZADD lb 2 B\r\nZCARD lb\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []interface{}{1, 1, 2} {
+		if got := c.read(); got != want {
+			t.Errorf("got %#v, want %#v", got, want)
+		}
+	}
+
+	c.expect("OK", "QUIT")
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := c.r.ReadByte(); err == nil {
+		t.Error("connection still open after QUIT")
+	}
+}
+
+func TestProtocolError(t *testing.T) {
+	c := startServer(t, SingleBoard(leaderboard.NewLeaderboardSkipList()))
+
+	if _, err := c.conn.Write([]byte("*1\r\n+PING\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err, ok := c.read().(error); !ok || !strings.Contains(err.Error(), "Protocol error") {
+		t.Errorf("got %v, want protocol error", err)
+	}
+}